@@ -3,15 +3,29 @@ package addressparser
 import (
 	"fmt"
 	"regexp"
-	"sort"
 	"strconv"
 	"strings"
+)
 
-	"github.com/pkg/errors"
-	"github.com/renstrom/fuzzysearch/fuzzy"
+// scores for fields found by position rather than a dictionary match
+const (
+	positionScore = 0.9
+	guessScore    = 0.5
 )
 
-const fuzzyScore = 10
+// address number patterns, compiled once
+var (
+	// 12-14
+	numberRangeRegex = regexp.MustCompile("^([0-9]+)-([0-9]+)$")
+	// 12A, not an ordinal
+	mixedNumberRegex = regexp.MustCompile("^([0-9]+)([A-Z]{1,2})$")
+	// A123B
+	postalNumberRegex = regexp.MustCompile("^([A-Z]{0,3})([0-9]+)([A-Z]{0,3})$")
+	// flat number / street number, eg. 3/123, 3A/12-14
+	slashNumberRegex = regexp.MustCompile("^([0-9]+[A-Z]{0,2})/([0-9]+[A-Z]{0,2}|[0-9]+-[0-9]+)$")
+	// 1ST, 22ND, 3RD, 11TH
+	ordinalRegex = regexp.MustCompile("^[0-9]*([0-9])(ST|ND|RD|TH)$")
+)
 
 func stringInSlice(str string, list []string) bool {
 	for _, v := range list {
@@ -22,79 +36,130 @@ func stringInSlice(str string, list []string) bool {
 	return false
 }
 
-func mapToSlice(mapData map[string]string) []string {
-	var output []string
-	for key, value := range mapData {
-		output = append(output, key, value)
+// check a string is only letters
+func isLetters(str string) bool {
+	if str == "" {
+		return false
 	}
-	return output
+	for i := 0; i < len(str); i++ {
+		char := str[i]
+		if (char < 'A' || char > 'Z') && (char < 'a' || char > 'z') {
+			return false
+		}
+	}
+	return true
 }
 
-func mapKeysToSlice(mapData map[string]string) []string {
-	var output []string
+// check a string is words of letters joined by hyphens or apostrophes, eg. FIRST-SECOND or O'CONNOR
+func isWord(str string) bool {
+	for _, word := range strings.Split(strings.Replace(str, "'", "-", -1), "-") {
+		if !isLetters(word) {
+			return false
+		}
+	}
+	return true
+}
 
-	for k := range mapData {
-		output = append(output, k)
+// check a string is an ordinal with the right suffix, eg. 1ST, 12TH, 22ND
+func isOrdinal(str string) bool {
+	match := ordinalRegex.FindStringSubmatch(str)
+	if match == nil {
+		return false
+	}
+	// 11TH, 12TH and 13TH are not 1ST, 2ND and 3RD
+	digits := str[:len(str)-2]
+	suffix := "TH"
+	if len(digits) < 2 || digits[len(digits)-2] != '1' {
+		switch match[1] {
+		case "1":
+			suffix = "ST"
+		case "2":
+			suffix = "ND"
+		case "3":
+			suffix = "RD"
+		}
 	}
-	return output
+	return match[2] == suffix
 }
 
-func getMatchKey(item string, mapData map[string]string) string {
-	for key, value := range mapData {
-		if key == item || value == item {
-			return key
+// check a string is only digits
+func isDigits(str string) bool {
+	if str == "" {
+		return false
+	}
+	for i := 0; i < len(str); i++ {
+		if str[i] < '0' || str[i] > '9' {
+			return false
 		}
 	}
-	return ""
+	return true
 }
 
-func fuzzyMatch(str string, mapList map[string]string) []string {
+// fuzzy match a string to a dictionary, returns the match and its distance.
+// an exact match has a distance of 0. when several entries are the same
+// distance the alphabetically first is used, so a string always gets the same match.
+// a match must be closer than maxDistance, see FuzzySearch for the exception
+func fuzzyMatch(str string, dict *Dictionary, similarity Similarity, maxDistance float64) ([]string, float64) {
 	var output []string
 	if str == "" {
-		return output
+		return output, 0
 	}
 
-	list := mapToSlice(mapList)
-
 	// check exact match
-	if stringInSlice(str, list) {
+	if dict.Has(str) {
 		output = append(output, str)
-		return output
+		return output, 0
 	}
-	// return if no exact macth for single char string
-	if len(str) == 1 {
-		return output
+	ordered, isOrdered := similarity.(orderedSimilarity)
+	if !isOrdered {
+		// short strings must match exactly, eg. QLD is not FL
+		if len(str) <= maxExactLength {
+			return output, 0
+		}
+		maxDistance = scaledThreshold(maxDistance, str)
 	}
-	// no exact match do fuzzy
-	fuzzyResults := fuzzy.RankFind(str, list)
-	if len(fuzzyResults) == 0 {
-		return output
+	// no exact match do fuzzy, closest first then alphabetically
+	var bestTarget string
+	var bestDistance float64
+	for _, target := range dict.list {
+		distance, ok := similarity.Distance(str, target)
+		if !ok {
+			continue
+		}
+		if bestTarget == "" || distance < bestDistance ||
+			(distance == bestDistance && target < bestTarget) {
+			bestTarget, bestDistance = target, distance
+		}
+	}
+	if bestTarget == "" {
+		return output, 0
 	}
-	// sort the results
-	sort.Sort(fuzzyResults)
 
-	if fuzzyResults[0].Distance >= fuzzyScore {
+	if bestDistance >= maxDistance {
 		// if distance is not within limits, check if partial result.
-		if !strings.HasSuffix(fuzzyResults[0].Target, fuzzyResults[0].Source) {
+		if !isOrdered || !ordered.matchesSuffix(str, bestTarget) {
 			// no results found
 			// return empty
-			return output
+			return output, 0
 		}
 	}
-	// return first item of results.
-	output = append(output, fuzzyResults[0].Target)
-	return output
+	// return the closest match
+	output = append(output, bestTarget)
+	return output, bestDistance
+}
+
+// convert a match distance to a score between 0 and 1
+func distanceScore(distance float64, target string) float64 {
+	if distance <= 0 {
+		return 1
+	}
+	return 1 - distance/(float64(len(target))+distance)
 }
 
 func splitNumberRange(addressPart string) (int, int) {
 	var start int
 	var end int
-	checkFileRegex, err := regexp.Compile("^([0-9]+)-([0-9]+)$")
-	if err != nil {
-		return start, end
-	}
-
-	matched := checkFileRegex.FindStringSubmatch(addressPart)
+	matched := numberRangeRegex.FindStringSubmatch(addressPart)
 	if matched == nil {
 		return start, end
 	}
@@ -106,12 +171,7 @@ func splitNumberRange(addressPart string) (int, int) {
 func splitMixedIndex(addressPart string) (int, string) {
 	var foundString string
 	var foundInt int
-	checkFileRegex, err := regexp.Compile("^([0-9]+)([A-Z]{1,2})$")
-	if err != nil {
-		return foundInt, foundString
-	}
-
-	matched := checkFileRegex.FindStringSubmatch(addressPart)
+	matched := mixedNumberRegex.FindStringSubmatch(addressPart)
 	if matched == nil {
 		return foundInt, foundString
 	}
@@ -120,6 +180,56 @@ func splitMixedIndex(addressPart string) (int, string) {
 	return foundInt, foundString
 }
 
+func splitPostalNumber(addressPart string) (string, int, string) {
+	var foundPrefix string
+	var foundInt int
+	var foundSuffix string
+	matched := postalNumberRegex.FindStringSubmatch(addressPart)
+	if matched == nil {
+		return foundPrefix, foundInt, foundSuffix
+	}
+	foundPrefix = matched[1]
+	foundInt, _ = strconv.Atoi(matched[2])
+	foundSuffix = matched[3]
+	return foundPrefix, foundInt, foundSuffix
+}
+
+func splitSlashNumber(addressPart string) (string, string) {
+	var flatPart string
+	var streetPart string
+	matched := slashNumberRegex.FindStringSubmatch(addressPart)
+	if matched == nil {
+		return flatPart, streetPart
+	}
+	flatPart = matched[1]
+	streetPart = matched[2]
+	return flatPart, streetPart
+}
+
+// split a number, mixed (12A) or range (12-14) into number, suffix and end
+func splitAddressNumber(addressPart string) (int, string, int) {
+	var addressNumber int
+	var addressNumberSuffix string
+	var addressNumberEnd int
+
+	if mixedNumberRegex.MatchString(addressPart) {
+		// set address type number and suffix
+		addressNumber, addressNumberSuffix = splitMixedIndex(addressPart)
+	} else if numberRangeRegex.MatchString(addressPart) {
+		addressNumber, addressNumberEnd = splitNumberRange(addressPart)
+	} else {
+		// set address type number
+		addressNumber, _ = strconv.Atoi(addressPart)
+	}
+	return addressNumber, addressNumberSuffix, addressNumberEnd
+}
+
+// Span - start and end byte offset of a field in the original address
+type Span struct {
+	Start int
+	End   int
+}
+
 // AddressParts - parts fo address found
 type AddressParts struct {
 	AddressString      string
@@ -139,54 +249,290 @@ type AddressParts struct {
 	Suburb             string
 	PostCode           int
 	State              string
+	// the street name has no street type, eg. THE PARADE or HIGHWAY 1
+	NoStreetType bool
+	// building or complex name, eg. RIALTO TOWERS
+	BuildingName string
+	// lot and the cadastral plan it is on, eg. LOT 5 DP 123456
+	LotNumber       int
+	LotNumberSuffix string
+	PlanType        string
+	PlanNumber      string
+	// postal delivery, eg. PO BOX 123
+	PostalDeliveryType         string
+	PostalDeliveryNumber       int
+	PostalDeliveryNumberPrefix string
+	PostalDeliveryNumberSuffix string
+	// second street of a corner, eg. CNR SMITH ST & JONES RD
+	CornerStreetName   string
+	CornerStreetType   string
+	CornerStreetSuffix string
+	// fields filled by Enrich rather than parsed
+	InferredFields []string
+
+	// the address string before it was cleaned
+	OriginalAddress string
+	// position of each parsed field in OriginalAddress
+	Spans map[string]Span
+	// dictionary match of each field found in a dictionary
+	Matches map[string]Match
+	// steps of the parse, only if the parser has WithTrace
+	Trace Trace
+
+	// position of each address part in OriginalAddress
+	partSpans []Span
+	// segment of each address part, a comma or line break starts a new segment
+	partSegments []int
+	// match score of each parsed field
+	fieldScores map[string]float64
+	// locality in the address lines that disagrees with the fields, see ParseLines
+	conflicts []string
+	// number of street types to skip when finding alternative parses
+	skipStreetTypes int
+	// parser with the options to parse with, nil for the defaults
+	parser *Parser
 }
 
 // NewAddress parse an address string into address struct
 func NewAddress(address string) (*AddressParts, error) {
-	var err error
-	addressParts := new(AddressParts)
-	err = addressParts.LoadAddressString(address)
-	if err != nil {
-		return addressParts, err
-	}
-	addressParts.ProcessAddress()
-
-	return addressParts, err
+	return defaultParser.Parse(address)
 }
 
 // LoadAddressString load a address string
 func (ap *AddressParts) LoadAddressString(addressString string) error {
 	var err error
-	reg, err := regexp.Compile("[^A-Za-z0-9 -]+")
-	if err != nil {
-		return errors.Wrap(err, "Failed to compile regex")
-	}
+	ap.OriginalAddress = addressString
+	ap.Spans = nil
+	ap.Matches = nil
 
-	addressString = strings.ToUpper(reg.ReplaceAllString(addressString, ""))
+	addressString, offsets := cleanAddressString(addressString)
 	ap.AddressString = addressString
 	ap.AddressStringParts = strings.Split(addressString, " ")
+
+	// position of each part in the original address
+	ap.partSpans = make([]Span, len(ap.AddressStringParts))
+	start := 0
+	for index, addressPart := range ap.AddressStringParts {
+		end := start + len(addressPart)
+		if addressPart != "" {
+			ap.partSpans[index] = Span{Start: offsets[start], End: offsets[end-1] + 1}
+		}
+		start = end + 1
+	}
+
+	// commas and line breaks split the address into segments, eg. the street line and the locality
+	ap.partSegments = make([]int, len(ap.AddressStringParts))
+	var markerIndex []int
+	segment := 0
+	for index, addressPart := range ap.AddressStringParts {
+		if addressPart == segmentMarker {
+			segment++
+			markerIndex = append(markerIndex, index)
+		}
+		ap.partSegments[index] = segment
+	}
+	ap.dropParts(markerIndex...)
+
+	// an & is only kept between the streets of a corner, eg. SMITH ST & JONES RD
+	if strings.Contains(addressString, "&") &&
+		ap.findIndex(2, ap.isPartCornerConnector, ap.isPartStreetTypeOrSuffix) == 0 {
+		var ampersandIndex []int
+		for index, addressPart := range ap.AddressStringParts {
+			if addressPart == "&" {
+				ampersandIndex = append(ampersandIndex, index)
+			}
+		}
+		ap.dropParts(ampersandIndex...)
+	}
+
+	for index := range ap.AddressStringParts {
+		// only keep the slash for unit/street numbers, eg. 3/123
+		if !ap.isPartSlashNumber(index) {
+			ap.AddressStringParts[index] = strings.Replace(ap.AddressStringParts[index], "/", "", -1)
+		}
+	}
 	if len(ap.AddressStringParts) < 3 {
-		return errors.New("Address string too short")
+		return &ParseError{Kind: ErrAddressTooShort, Parts: ap.AddressStringParts}
 	}
 
 	return err
 }
 
+// typographic apostrophe, eg. O’CONNOR
+const rightQuote = "\u2019"
+
+// part that commas and line breaks are cleaned to
+const segmentMarker = ","
+
+func isSeparatePart(char byte) bool {
+	return char == '&' || char == segmentMarker[0]
+}
+
+// uppercase and remove punctuation from an address string, returning
+// the offset in the original string of each byte kept. apostrophes
+// inside a name are kept, eg. O'CONNOR
+func cleanAddressString(addressString string) (string, []int) {
+	var cleaned []byte
+	var offsets []int
+
+	for i := 0; i < len(addressString); i++ {
+		char := addressString[i]
+		start := i
+		if strings.HasPrefix(addressString[i:], rightQuote) {
+			char = '\''
+			i += len(rightQuote) - 1
+		}
+		switch {
+		case char >= 'a' && char <= 'z':
+			char -= 'a' - 'A'
+		case char >= 'A' && char <= 'Z', char >= '0' && char <= '9':
+		case char == ' ', char == '/', char == '-', char == '&':
+		case char == ',', char == '\n', char == '\r':
+			char = segmentMarker[0]
+		case char == '\'':
+			// apostrophes only inside a name, eg. O'CONNOR
+			if len(cleaned) == 0 || !isLetters(string(cleaned[len(cleaned)-1])) ||
+				i+1 >= len(addressString) || !isLetters(addressString[i+1:i+2]) {
+				continue
+			}
+		default:
+			continue
+		}
+		// & and segment markers are always a part of their own, eg. SMITH ST&JONES RD or SMITH ST,FITZROY
+		if len(cleaned) > 0 && char != ' ' && cleaned[len(cleaned)-1] != ' ' &&
+			(isSeparatePart(char) || isSeparatePart(cleaned[len(cleaned)-1])) {
+			cleaned = append(cleaned, ' ')
+			offsets = append(offsets, start)
+		}
+		// remove spaces around a slash, eg. 3 / 123
+		if char == '/' {
+			for len(cleaned) > 0 && cleaned[len(cleaned)-1] == ' ' {
+				cleaned = cleaned[:len(cleaned)-1]
+				offsets = offsets[:len(offsets)-1]
+			}
+		}
+		if char == ' ' && len(cleaned) > 0 && cleaned[len(cleaned)-1] == '/' {
+			continue
+		}
+		cleaned = append(cleaned, char)
+		offsets = append(offsets, start)
+	}
+
+	return string(cleaned), offsets
+}
+
 // ProcessAddress process the address parts
 func (ap *AddressParts) ProcessAddress() {
 	var foundIndex int
+	var postalIndex int
+	// index the street starts at, the street number or name
+	streetIndex := -1
+
+	ap.Trace = nil
+	if ap.config().trace {
+		ap.Trace = Trace{}
+	}
+
+	// look for known building names, see WithBuildingNames
+	ap.matchKnownBuilding()
+
+	// look for a lot number, eg. LOT 12
+	ap.traceStep("lot")
+	for index, addressPart := range ap.AddressStringParts {
+		if ap.dicts().LotTypes.Has(addressPart) && ap.hasPartIndex(index+1) && ap.isPartNumberOrMixed(index+1) {
+			ap.LotNumber, ap.LotNumberSuffix, _ = splitAddressNumber(ap.AddressStringParts[index+1])
+			ap.setField("LotNumber", 1, index, index+1)
+			ap.removeParts(index, index+1)
+			break
+		}
+	}
+
+	// look for a plan, eg. DP 123456 or ON SP 12345
+	ap.traceStep("plan")
+	for index := range ap.AddressStringParts {
+		matchedPlanType, matchedIndex := ap.matchCompactKey(index, ap.dicts().PlanTypes)
+		numberIndex := index + len(matchedIndex)
+		if matchedPlanType == "" || !ap.hasPartIndex(numberIndex) || !ap.isPartNumberOrMixed(numberIndex) {
+			continue
+		}
+		ap.PlanType = matchedPlanType
+		ap.PlanNumber = ap.AddressStringParts[numberIndex]
+		ap.setField("PlanType", 1, matchedIndex...)
+		ap.setField("PlanNumber", 1, numberIndex)
+		ap.removeParts(append(matchedIndex, numberIndex)...)
+		if ap.hasPartIndex(index-1) && ap.AddressStringParts[index-1] == "ON" {
+			ap.removeParts(index - 1)
+		}
+		break
+	}
+
+	// look for postal delivery types, PO BOX, LOCKED BAG etc.
+	ap.traceStep("postal delivery")
+	for index := range ap.AddressStringParts {
+		matchedPostalType, matchedIndex := ap.matchPostalDeliveryType(index)
+		if matchedPostalType == "" {
+			continue
+		}
+		postalIndex = matchedIndex[len(matchedIndex)-1]
+		if !ap.addressPartNoNumber(matchedPostalType) {
+			// postal delivery types need a number after them
+			postalIndex++
+			if !ap.hasPartIndex(postalIndex) {
+				continue
+			}
+			prefix, number, suffix := splitPostalNumber(ap.AddressStringParts[postalIndex])
+			if number == 0 {
+				continue
+			}
+			ap.PostalDeliveryNumberPrefix = prefix
+			ap.PostalDeliveryNumber = number
+			ap.PostalDeliveryNumberSuffix = suffix
+			ap.setField("PostalDeliveryNumber", 1, postalIndex)
+			ap.removeParts(postalIndex)
+		}
+		ap.PostalDeliveryType = matchedPostalType
+		ap.setField("PostalDeliveryType", 1, matchedIndex...)
+		ap.removeParts(matchedIndex...)
+		break
+	}
+
+	// look for slash notation, eg. 3/123 is flat 3 at street number 123
+	ap.traceStep("slash number")
+	for index := range ap.AddressStringParts {
+		if !ap.isPartSlashNumber(index) {
+			continue
+		}
+		flatPart, _ := splitSlashNumber(ap.AddressStringParts[index])
+		ap.FlatNumber, ap.FlatNumberSuffix, _ = splitAddressNumber(flatPart)
+		// flat type is optional, eg. UNIT 3/123
+		if ap.isPartString(index - 1) {
+			matchedFlatType, matchedIndex, match := ap.matchAddressPart(index-1, ap.dicts().FlatTypes)
+			if matchedFlatType != "" {
+				ap.FlatType = matchedFlatType
+				ap.setMatch("FlatType", match, matchedIndex...)
+				ap.removeParts(matchedIndex...)
+			}
+		}
+		ap.setField("FlatNumber", 1, index)
+		// the street number is left for the street number lookup
+		break
+	}
 
 	// first part is string, most level or flat type
 	if ap.isPartString(0) {
 
 		// look for flat types
+		ap.traceStep("flat type")
 		foundIndex = ap.findIndex(1, ap.isPartAnyNumber, ap.isPartString)
-		if foundIndex != 0 {
-			matchedFlatType, matchedIndex := ap.matchAddressPart(foundIndex-1, flatTypes)
+		// the number is on the same line as the type, eg. TOWERS at the end of the line before 525 is not TWR 525
+		if foundIndex != 0 && ap.sameSegment(foundIndex-1, foundIndex) {
+			matchedFlatType, matchedIndex, match := ap.matchAddressPart(foundIndex-1, ap.dicts().FlatTypes)
 			if matchedFlatType != "" {
 				ap.FlatType = matchedFlatType
+				ap.setMatch("FlatType", match, matchedIndex...)
 				if !ap.addressPartNoNumber(matchedFlatType) {
 					ap.FlatNumber, ap.FlatNumberSuffix, _ = ap.getAddressNumber(foundIndex)
+					ap.setField("FlatNumber", 1, foundIndex)
 					ap.removeParts(foundIndex)
 				}
 				ap.removeParts(matchedIndex...)
@@ -194,13 +540,16 @@ func (ap *AddressParts) ProcessAddress() {
 		}
 
 		// look for level types
+		ap.traceStep("level type")
 		foundIndex = ap.findIndex(1, ap.isPartAnyNumber, ap.isPartString)
-		if foundIndex != 0 {
-			matchedLevelType, matchedIndex := ap.matchAddressPart(foundIndex-1, levelTypes)
+		if foundIndex != 0 && ap.sameSegment(foundIndex-1, foundIndex) {
+			matchedLevelType, matchedIndex, match := ap.matchAddressPart(foundIndex-1, ap.dicts().LevelTypes)
 			if matchedLevelType != "" {
 				ap.LevelType = matchedLevelType
+				ap.setMatch("LevelType", match, matchedIndex...)
 				if !ap.addressPartNoNumber(matchedLevelType) {
 					ap.LevelNumber, _, _ = ap.getAddressNumber(foundIndex)
+					ap.setField("LevelNumber", 1, foundIndex)
 					ap.removeParts(foundIndex)
 				}
 				ap.removeParts(matchedIndex...)
@@ -211,77 +560,218 @@ func (ap *AddressParts) ProcessAddress() {
 
 	// if last part is a number it is prossibly a postcode
 	lastIndex := len(ap.AddressStringParts) - 1
+	ap.traceStep("postcode")
 	if ap.isPartNumber(lastIndex) {
 		postCode := ap.matchPostCode(lastIndex)
 		if postCode != 0 {
 			ap.PostCode = postCode
+			ap.setField("PostCode", 1, lastIndex)
 			ap.removeParts(lastIndex)
 		}
 	}
 
 	// look for the state - usually the last string on the address
+	ap.traceStep("state")
 	foundIndex = ap.findIndexReverse(lastIndex, ap.isPartString, ap.isPartAny)
 	if foundIndex != 0 {
-		matchedState, matchedIndex := ap.matchAddressPart(foundIndex, australianStates)
+		matchedState, matchedIndex, match := ap.matchAddressPart(foundIndex, ap.dicts().States)
 
 		if matchedState != "" {
 			ap.State = matchedState
+			ap.setMatch("State", match, matchedIndex...)
 			ap.removeParts(matchedIndex...)
 		}
 	}
 
-	// look for the state - usually the last string on the address followed by a string
-	foundIndex = ap.findIndex(1, ap.isPartString, ap.isPartAnyNumber)
+	// postal addresses have the suburb after the postal delivery number
+	if ap.PostalDeliveryType != "" {
+		ap.traceStep("postal suburb")
+		var matchedIndex []int
+		ap.Suburb, matchedIndex = ap.getStringAfter(postalIndex)
+		if ap.Suburb != "" {
+			ap.setField("Suburb", positionScore, matchedIndex...)
+		}
+		ap.removeParts(matchedIndex...)
+	}
+
+	// look for a corner, eg. CNR SMITH ST & JONES RD
+	ap.traceStep("corner")
+	foundIndex = ap.findIndex(2, ap.isPartCornerConnector, ap.isPartStreetTypeOrSuffix)
+	if foundIndex != 0 {
+		ap.matchCorner(foundIndex)
+	}
+	// look for the street number - a number followed by a string
+	ap.traceStep("street number")
+	foundIndex = ap.findIndex(1, ap.isPartString, ap.isPartStreetNumber)
 	if foundIndex != 0 {
 		ap.StreetNumber,
 			ap.StreetNumberSuffix,
 			ap.StreetNumberEnd = ap.getAddressNumber(foundIndex - 1)
+		ap.setField("StreetNumber", 1, foundIndex-1)
 		ap.removeParts(foundIndex - 1)
+		streetIndex = foundIndex - 1
 	}
 
-	// find street type
-	foundIndex = ap.findIndex(1, ap.isPartStreetType, ap.isPartString)
+	// find street type, after the street number if there is one
+	ap.traceStep("street type")
+	foundIndex = ap.findIndex(streetIndex+2, ap.isPartNumberedStreet, ap.isPartAny)
 	if foundIndex != 0 {
-		if (foundIndex-1 >= 0) && ap.AddressStringParts[foundIndex-1] == "THE" {
+		// the number is the street name, eg. HIGHWAY 1
+		ap.StreetName = fmt.Sprintf(
+			"%s %s",
+			ap.dicts().StreetTypes.Key(ap.AddressStringParts[foundIndex-1]),
+			ap.AddressStringParts[foundIndex],
+		)
+		ap.NoStreetType = true
+		ap.setField("StreetName", 1, foundIndex-1, foundIndex)
+		ap.removeParts(foundIndex-1, foundIndex)
+	} else if saintIndex := ap.findIndex(streetIndex+2, ap.isPartSaint, ap.isPartStreetTypeName); saintIndex > 1 {
+		// a street type before a saint is the street name, eg. ESPLANADE ST KILDA
+		foundIndex = saintIndex - 1
+		ap.StreetName = ap.AddressStringParts[foundIndex]
+		ap.NoStreetType = true
+		ap.setField("StreetName", 1, foundIndex)
+		ap.removeParts(foundIndex)
+	} else {
+		foundIndex = ap.findStreetType(streetIndex + 2)
+		for skip := ap.skipStreetTypes; skip > 0 && foundIndex != 0; skip-- {
+			// alternative parse, the street type is part of the street name
+			foundIndex = ap.findStreetType(foundIndex + 1)
+		}
+		if foundIndex == 0 {
+			// THE and a name without a street type, eg. THE KNOLL
+			foundIndex = ap.findIndex(streetIndex+2, ap.isPartString, ap.isPartThe)
+		}
+	}
+	if foundIndex != 0 && ap.StreetName == "" {
+		if ap.isPartThe(foundIndex - 1) {
+			// the street type is the name, eg. THE PARADE
 			ap.StreetName = fmt.Sprintf(
 				"%s %s",
 				ap.AddressStringParts[foundIndex-1],
 				ap.AddressStringParts[foundIndex],
 			)
-			ap.StreetType = "-"
+			ap.NoStreetType = true
+			ap.setField("StreetName", 1, foundIndex-1, foundIndex)
 			ap.removeParts(foundIndex-1, foundIndex)
 		} else {
-			ap.StreetType = getMatchKey(ap.AddressStringParts[foundIndex], streetTypes)
+			matchResult, matchDistance := ap.config().fuzzyMatch(ap.AddressStringParts[foundIndex], ap.dicts().StreetTypes)
+			ap.StreetType = ap.dicts().StreetTypes.Key(matchResult[0])
+			ap.setMatch("StreetType", Match{
+				Part:     ap.AddressStringParts[foundIndex],
+				Entry:    matchResult[0],
+				Distance: matchDistance,
+			}, foundIndex)
 			ap.removeParts(foundIndex)
 		}
 	}
 
 	// before street type should be street Name
-	//
+	ap.traceStep("street name")
 	if foundIndex > 0 && ap.StreetName == "" {
 		var matchedIndex []int
 		ap.StreetName, matchedIndex = ap.getStringBefore(foundIndex)
+		ap.setField("StreetName", 1, matchedIndex...)
 		ap.removeParts(matchedIndex...)
-	} else if ap.StreetType == "" && ap.StreetName == "" && ap.isPartString(lastIndex) {
+		if streetIndex < 0 && len(matchedIndex) > 0 {
+			streetIndex = matchedIndex[len(matchedIndex)-1]
+		}
+	} else if ap.StreetType == "" && !ap.NoStreetType && ap.StreetName == "" && ap.isPartString(lastIndex) {
 		// street name/type not found, last string might be the street name
 		ap.StreetName = ap.AddressStringParts[lastIndex]
+		ap.setField("StreetName", guessScore, lastIndex)
+	}
+	if streetIndex < 0 && ap.NoStreetType {
+		streetIndex = foundIndex - 1
 	}
 
 	// after street type should be suburb
 	nextIndex := foundIndex + 1
 	if foundIndex > 0 && ap.hasPartIndex(nextIndex) {
 		// look for street suffix
-		streetSuffixesKeys := mapKeysToSlice(streetSuffixes)
-		if stringInSlice(ap.AddressStringParts[nextIndex], streetSuffixesKeys) {
-			ap.StreetSuffix = ap.AddressStringParts[nextIndex]
+		ap.traceStep("street suffix")
+		if ap.isPartStreetSuffix(nextIndex) && ap.sameSegment(foundIndex, nextIndex) {
+			ap.StreetSuffix = ap.dicts().StreetSuffixes.Key(ap.AddressStringParts[nextIndex])
+			ap.setField("StreetSuffix", 1, nextIndex)
 			ap.removeParts(nextIndex)
 			foundIndex = nextIndex
 		}
-		var matchedIndex []int
-		ap.Suburb, matchedIndex = ap.getStringAfter(foundIndex)
+		ap.traceStep("suburb")
+		suburb, matchedIndex := ap.getStringAfter(foundIndex)
+		if suburb != "" {
+			ap.Suburb = suburb
+			ap.setField("Suburb", positionScore, matchedIndex...)
+		}
 		ap.removeParts(matchedIndex...)
 	}
 
+	// words left before the street are a building name, eg. RIALTO TOWERS 525 COLLINS ST
+	if ap.BuildingName == "" && streetIndex > 0 {
+		ap.traceStep("building name")
+		ap.matchBuildingName(streetIndex)
+	}
+
+}
+
+// parser options used to parse the address
+func (ap *AddressParts) config() *Parser {
+	if ap.parser == nil {
+		return defaultParser
+	}
+	return ap.parser
+}
+
+// dictionaries used to parse the address
+func (ap *AddressParts) dicts() *Dictionaries {
+	return ap.config().dictionaries
+}
+
+// record the dictionary match of a field, its score and span
+func (ap *AddressParts) setMatch(field string, match Match, indexes ...int) {
+	if ap.Matches == nil {
+		ap.Matches = make(map[string]Match)
+	}
+	ap.Matches[field] = match
+	ap.setField(field, distanceScore(match.Distance, match.Entry), indexes...)
+}
+
+// record the match score of a field and its span from the parts it was found in
+func (ap *AddressParts) setField(field string, score float64, indexes ...int) {
+	if ap.fieldScores == nil {
+		ap.fieldScores = make(map[string]float64)
+	}
+	ap.fieldScores[field] = score
+	ap.traceField(field)
+
+	var span Span
+	for _, index := range indexes {
+		if index < 0 || index >= len(ap.partSpans) {
+			continue
+		}
+		partSpan := ap.partSpans[index]
+		if span.End == 0 || partSpan.Start < span.Start {
+			span.Start = partSpan.Start
+		}
+		if partSpan.End > span.End {
+			span.End = partSpan.End
+		}
+	}
+	if span.End == 0 {
+		return
+	}
+	if ap.Spans == nil {
+		ap.Spans = make(map[string]Span)
+	}
+	ap.Spans[field] = span
+}
+
+// Original get the text of a parsed field from the original address
+func (ap *AddressParts) Original(field string) string {
+	span, ok := ap.Spans[field]
+	if !ok || span.End > len(ap.OriginalAddress) {
+		return ""
+	}
+	return ap.OriginalAddress[span.Start:span.End]
 }
 
 func (ap *AddressParts) hasPartIndex(index int) bool {
@@ -292,51 +782,263 @@ func (ap *AddressParts) hasPartIndex(index int) bool {
 	return false
 }
 
+// drop parts from the address, only before any part indexes are kept
+func (ap *AddressParts) dropParts(indexes ...int) {
+	var addressStringParts []string
+	var partSpans []Span
+	var partSegments []int
+	for index, addressPart := range ap.AddressStringParts {
+		if intInSlice(index, indexes) {
+			ap.traceRemoved(index)
+			continue
+		}
+		addressStringParts = append(addressStringParts, addressPart)
+		partSpans = append(partSpans, ap.partSpans[index])
+		partSegments = append(partSegments, ap.partSegments[index])
+	}
+	ap.AddressStringParts, ap.partSpans, ap.partSegments = addressStringParts, partSpans, partSegments
+	ap.AddressString = strings.Join(addressStringParts, " ")
+}
+
 func (ap *AddressParts) removeParts(indexes ...int) {
 	for _, index := range indexes {
+		ap.traceRemoved(index)
 		ap.AddressStringParts[index] = ""
 	}
 }
 
 func (ap *AddressParts) addressPartNoNumber(addressPart string) bool {
-	addressTypesNoNumberSlice := mapToSlice(addressTypesNoNumber)
-	return stringInSlice(addressPart, addressTypesNoNumberSlice)
+	return ap.dicts().NoNumberTypes.Has(addressPart)
 }
 
-func (ap *AddressParts) matchAddressPart(index int, addressTypes map[string]string) (string, []int) {
+func (ap *AddressParts) matchAddressPart(
+	index int, addressTypes *Dictionary,
+) (string, []int, Match) {
 
 	var matchedPart string
 	var matchedIndex []int
 	var matchResult []string
+	var matchDistance float64
+	var words string
+	var examinedIndex []int
 
 	for i := index; i >= 0; i-- {
 		var currentPart string
-		if matchedPart == "" {
+		if words == "" {
 			currentPart = ap.AddressStringParts[i]
 		} else {
-			currentPart = fmt.Sprintf("%s %s", ap.AddressStringParts[i], matchedPart)
+			currentPart = fmt.Sprintf("%s %s", ap.AddressStringParts[i], words)
 		}
 
-		result := fuzzyMatch(currentPart, addressTypes)
+		result, distance := ap.config().fuzzyMatch(currentPart, addressTypes)
+		ap.traceMatch(currentPart, result, distance)
 
-		if result == nil {
+		if result == nil && !addressTypes.hasEnding(currentPart) {
 			// no match - quit loop
 			break
 		}
+		words = currentPart
+		examinedIndex = append(examinedIndex, i)
+		if result == nil {
+			// the end of a longer entry, eg. GROUND FLOOR of LOWER GROUND FLOOR
+			continue
+		}
 		matchResult = result
-		matchedIndex = append(matchedIndex, i)
+		matchDistance = distance
+		matchedIndex = append([]int{}, examinedIndex...)
 
 		matchedPart = currentPart
 	}
 
+	match := Match{Part: matchedPart, Distance: matchDistance}
 	if len(matchResult) == 1 {
 		matchedPart = matchResult[0]
 	}
+	match.Entry = matchedPart
 
 	// switch the matched part to the key (code) value
-	matchedPart = getMatchKey(matchedPart, addressTypes)
+	matchedPart = addressTypes.Key(matchedPart)
+
+	return matchedPart, matchedIndex, match
+}
+
+// match a known building name, the longest name first. this is the first
+// step, the building name parts are dropped rather than removed
+func (ap *AddressParts) matchKnownBuilding() {
+	buildingNames := ap.config().buildingNames
+	if len(buildingNames) == 0 {
+		return
+	}
+	ap.traceStep("known building")
+
+	for index := range ap.AddressStringParts {
+		for length := ap.config().buildingNameLength; length > 0; length-- {
+			lastIndex := index + length - 1
+			if !ap.hasPartIndex(lastIndex) {
+				continue
+			}
+			matchedIndex := make([]int, 0, length)
+			for i := index; i <= lastIndex && ap.AddressStringParts[i] != ""; i++ {
+				matchedIndex = append(matchedIndex, i)
+			}
+			if len(matchedIndex) != length {
+				continue
+			}
+			buildingName := strings.Join(ap.AddressStringParts[index:lastIndex+1], " ")
+			if buildingNames[NameKey(buildingName)] {
+				ap.BuildingName = buildingName
+				ap.setField("BuildingName", 1, matchedIndex...)
+				// dropped so the parts either side are next to each other
+				ap.dropParts(matchedIndex...)
+				return
+			}
+		}
+	}
+}
+
+// match the words left before the street as a building name, they must be
+// next to each other, eg. WESTFIELD DONCASTER SHOP 12 619 DONCASTER RD
+func (ap *AddressParts) matchBuildingName(streetIndex int) {
+	var matchedIndex []int
+	for index := 0; index < streetIndex; index++ {
+		if ap.AddressStringParts[index] == "" {
+			continue
+		}
+		if !ap.isPartString(index) {
+			return
+		}
+		if len(matchedIndex) > 0 && !ap.sameSegment(index, matchedIndex[0]) {
+			// the building is the line before the street, eg. JOHN SMITH, RIALTO TOWERS, 525 COLLINS ST
+			matchedIndex = nil
+		}
+		if len(matchedIndex) > 0 && matchedIndex[len(matchedIndex)-1] != index-1 {
+			return
+		}
+		matchedIndex = append(matchedIndex, index)
+	}
+	if len(matchedIndex) == 0 {
+		return
+	}
+	// a name or recipient before the street isn't a building, eg. JOHN SMITH 12 SMITH ST,
+	// unless it is its own line or next to the flat or level
+	firstIndex, lastIndex := matchedIndex[0], matchedIndex[len(matchedIndex)-1]
+	if ap.sameSegment(lastIndex, streetIndex) &&
+		!ap.isPartField(firstIndex-1, subDwellingFields...) && !ap.isPartField(lastIndex+1, subDwellingFields...) {
+		return
+	}
+
+	buildingName := make([]string, len(matchedIndex))
+	for i, index := range matchedIndex {
+		buildingName[i] = ap.AddressStringParts[index]
+	}
+	ap.BuildingName = strings.Join(buildingName, " ")
+	ap.setField("BuildingName", guessScore, matchedIndex...)
+	ap.removeParts(matchedIndex...)
+}
+
+// fields of the flat and level
+var subDwellingFields = []string{"FlatType", "FlatNumber", "LevelType", "LevelNumber"}
+
+// check an address part was parsed as one of the fields
+func (ap *AddressParts) isPartField(index int, fields ...string) bool {
+	if index < 0 || index >= len(ap.partSpans) {
+		return false
+	}
+	partSpan := ap.partSpans[index]
+	for _, field := range fields {
+		span, ok := ap.Spans[field]
+		if ok && span.Start <= partSpan.Start && partSpan.End <= span.End {
+			return true
+		}
+	}
+	return false
+}
+
+// match the second street of a corner after the & or AND at connectorIndex,
+// the first street is left for the street steps. the suburb is after the second street
+func (ap *AddressParts) matchCorner(connectorIndex int) {
+	typeIndex := ap.findIndex(connectorIndex+2, ap.isPartStreetType, ap.isPartString)
+	if typeIndex == 0 {
+		return
+	}
+	ap.removeParts(connectorIndex)
+
+	var matchedIndex []int
+	ap.CornerStreetName, matchedIndex = ap.getStringBefore(typeIndex)
+	ap.setField("CornerStreetName", 1, matchedIndex...)
+	ap.removeParts(matchedIndex...)
+
+	matchResult, matchDistance := ap.config().fuzzyMatch(ap.AddressStringParts[typeIndex], ap.dicts().StreetTypes)
+	ap.CornerStreetType = ap.dicts().StreetTypes.Key(matchResult[0])
+	ap.setMatch("CornerStreetType", Match{
+		Part:     ap.AddressStringParts[typeIndex],
+		Entry:    matchResult[0],
+		Distance: matchDistance,
+	}, typeIndex)
+	ap.removeParts(typeIndex)
+
+	lastIndex := typeIndex
+	if ap.hasPartIndex(typeIndex+1) && ap.dicts().StreetSuffixes.HasKey(ap.AddressStringParts[typeIndex+1]) {
+		lastIndex = typeIndex + 1
+		ap.CornerStreetSuffix = ap.AddressStringParts[lastIndex]
+		ap.setField("CornerStreetSuffix", 1, lastIndex)
+		ap.removeParts(lastIndex)
+	}
+	ap.Suburb, matchedIndex = ap.getStringAfter(lastIndex)
+	if ap.Suburb != "" {
+		ap.setField("Suburb", positionScore, matchedIndex...)
+	}
+	ap.removeParts(matchedIndex...)
+
+	// CNR or CORNER OF before the first street
+	for index, addressPart := range ap.AddressStringParts {
+		if addressPart == "" {
+			continue
+		}
+		if addressPart == "CNR" || addressPart == "CORNER" {
+			ap.removeParts(index)
+			if ap.hasPartIndex(index+1) && ap.AddressStringParts[index+1] == "OF" {
+				ap.removeParts(index + 1)
+			}
+		}
+		break
+	}
+}
 
-	return matchedPart, matchedIndex
+// match postal delivery types that can span multiple parts, eg. PO BOX, P O BOX
+func (ap *AddressParts) matchPostalDeliveryType(index int) (string, []int) {
+	return ap.matchCompactKey(index, ap.dicts().PostalDeliveryTypes)
+}
+
+// match a dictionary key or value of up to 4 parts from index, ignoring
+// spaces, eg. P O BOX or DEPOSITED PLAN
+func (ap *AddressParts) matchCompactKey(index int, dict *Dictionary) (string, []int) {
+	var matchedIndex []int
+
+	for length := 4; length > 0; length-- {
+		lastIndex := index + length - 1
+		if !ap.hasPartIndex(lastIndex) {
+			continue
+		}
+		var joinedPart string
+		matchedIndex = nil
+		for i := index; i <= lastIndex; i++ {
+			if !ap.isPartString(i) {
+				break
+			}
+			joinedPart += ap.AddressStringParts[i]
+			matchedIndex = append(matchedIndex, i)
+		}
+		if len(matchedIndex) != length {
+			continue
+		}
+		if key, ok := dict.compactKeys[joinedPart]; ok {
+			ap.traceAttempt(Match{Part: joinedPart, Entry: key})
+			return key, matchedIndex
+		}
+	}
+
+	return "", nil
 }
 
 func (ap *AddressParts) getStringBefore(startIndex int) (string, []int) {
@@ -346,7 +1048,7 @@ func (ap *AddressParts) getStringBefore(startIndex int) (string, []int) {
 	for i := (startIndex - 1); i >= 0; i-- {
 		addressPart := ap.AddressStringParts[i]
 
-		if !ap.isPartString(i) {
+		if !ap.isPartString(i) || !ap.sameSegment(i, startIndex) {
 			return matchedString, matchedIndex
 		}
 		if matchedString == "" {
@@ -368,7 +1070,7 @@ func (ap *AddressParts) getStringAfter(startIndex int) (string, []int) {
 	for i := startIndex; i < len(ap.AddressStringParts); i++ {
 		addressPart := ap.AddressStringParts[i]
 
-		if !ap.isPartString(i) {
+		if !ap.isPartString(i) || (len(matchedIndex) > 0 && !ap.sameSegment(i, matchedIndex[0])) {
 			return matchedString, matchedIndex
 		}
 		if matchedString == "" {
@@ -383,44 +1085,36 @@ func (ap *AddressParts) getStringAfter(startIndex int) (string, []int) {
 }
 
 func (ap *AddressParts) getAddressNumber(index int) (int, string, int) {
-	var addressNumber int
-	var addressNumberSuffix string
-	var addressNumberEnd int
-
-	if ap.isPartMixed(index) {
-		// set address type number and suffix
-		addressNumber, addressNumberSuffix = splitMixedIndex(ap.AddressStringParts[index])
-	} else if ap.isPartNumberRange(index) {
-		addressNumber, addressNumberEnd = splitNumberRange(ap.AddressStringParts[index])
-	} else {
-		// set address type number
-		addressNumber, _ = strconv.Atoi(ap.AddressStringParts[index])
+	addressPart := ap.AddressStringParts[index]
+	if ap.isPartSlashNumber(index) {
+		// street number is after the slash
+		_, addressPart = splitSlashNumber(addressPart)
 	}
-	// clear address type number
-	return addressNumber, addressNumberSuffix, addressNumberEnd
+	return splitAddressNumber(addressPart)
 }
 
 func (ap *AddressParts) isPartString(index int) bool {
-	if index < 0 {
+	if !ap.hasPartIndex(index) {
 		return false
 	}
-	matched, _ := regexp.MatchString("^[a-zA-Z]+$", ap.AddressStringParts[index])
-	return matched
+	return isWord(ap.AddressStringParts[index]) || isOrdinal(ap.AddressStringParts[index])
 }
 
 func (ap *AddressParts) isPartNumber(index int) bool {
-	matched, _ := regexp.MatchString("^[0-9]+$", ap.AddressStringParts[index])
-	return matched
+	return ap.hasPartIndex(index) && isDigits(ap.AddressStringParts[index])
 }
 
 func (ap *AddressParts) isPartNumberRange(index int) bool {
-	matched, _ := regexp.MatchString("^([0-9]+)-([0-9]+)$", ap.AddressStringParts[index])
-	return matched
+	return numberRangeRegex.MatchString(ap.AddressStringParts[index])
 }
 
 func (ap *AddressParts) isPartMixed(index int) bool {
-	matched, _ := regexp.MatchString("^([0-9]+)([A-Z]{1,2})$", ap.AddressStringParts[index])
-	return matched
+	return mixedNumberRegex.MatchString(ap.AddressStringParts[index]) &&
+		!isOrdinal(ap.AddressStringParts[index])
+}
+
+func (ap *AddressParts) isPartSlashNumber(index int) bool {
+	return slashNumberRegex.MatchString(ap.AddressStringParts[index])
 }
 
 func (ap *AddressParts) isPartNumberOrMixed(index int) bool {
@@ -428,18 +1122,132 @@ func (ap *AddressParts) isPartNumberOrMixed(index int) bool {
 }
 
 func (ap *AddressParts) isPartAnyNumber(index int) bool {
-	return (ap.isPartMixed(index) || ap.isPartNumber(index) || ap.isPartNumberRange(index))
+	return (ap.isPartMixed(index) || ap.isPartNumber(index) ||
+		ap.isPartNumberRange(index) || ap.isPartSlashNumber(index))
+}
+
+// find a street type after a street name. a street type word followed by an
+// abbreviated street type is part of the name, eg. GROVE PARADE ST
+func (ap *AddressParts) findStreetType(startIndex int) int {
+	foundIndex := ap.findIndex(startIndex, ap.isPartStreetType, ap.isPartString)
+	for foundIndex != 0 && ap.dicts().StreetTypes.HasKey(ap.AddressStringParts[foundIndex]) &&
+		ap.isPartAbbreviatedStreetType(foundIndex+1) && ap.sameSegment(foundIndex, foundIndex+1) {
+		foundIndex++
+	}
+	if foundIndex == 0 || ap.isLastSegment(foundIndex) {
+		return foundIndex
+	}
+
+	// a street line before the locality ends with the street type, eg. GROVE PARADE ST, FITZROY
+	endIndex := ap.segmentEnd(foundIndex)
+	if endIndex > foundIndex && ap.dicts().StreetSuffixes.Has(ap.AddressStringParts[endIndex]) {
+		endIndex--
+	}
+	// an abbreviated type isn't a name before a full word type, eg. SMITH RD CLOSE
+	abbreviated := !ap.dicts().StreetTypes.HasKey(ap.AddressStringParts[foundIndex])
+	if endIndex > foundIndex && ap.dicts().StreetTypes.Has(ap.AddressStringParts[endIndex]) &&
+		ap.isPartString(endIndex-1) && !(abbreviated && ap.dicts().StreetTypes.HasKey(ap.AddressStringParts[endIndex])) {
+		foundIndex = endIndex
+	}
+	return foundIndex
+}
+
+// a street suffix, eg. N or NORTH. a full word must end the line, as
+// SMITH ST NORTH MELBOURNE is in NORTH MELBOURNE
+func (ap *AddressParts) isPartStreetSuffix(index int) bool {
+	if !ap.hasPartIndex(index) {
+		return false
+	}
+	part := ap.AddressStringParts[index]
+	if ap.dicts().StreetSuffixes.HasKey(part) {
+		return true
+	}
+	return ap.dicts().StreetSuffixes.Has(part) && ap.segmentEnd(index) == index && !ap.isLastSegment(index)
+}
+
+// an abbreviation of a street type, eg. RD. ST before a name may be SAINT, eg. SMITH ESPLANADE ST KILDA
+func (ap *AddressParts) isPartAbbreviatedStreetType(index int) bool {
+	if !ap.hasPartIndex(index) || ap.isPartSaint(index) {
+		return false
+	}
+	part := ap.AddressStringParts[index]
+	return ap.dicts().StreetTypes.Has(part) && !ap.dicts().StreetTypes.HasKey(part)
+}
+
+// ST before a name on the same line, eg. ST KILDA
+func (ap *AddressParts) isPartSaint(index int) bool {
+	return ap.hasPartIndex(index) && ap.AddressStringParts[index] == "ST" &&
+		ap.isPartString(index+1) && ap.sameSegment(index, index+1)
+}
+
+// a full word street type with no name before it, eg. ESPLANADE
+func (ap *AddressParts) isPartStreetTypeName(index int) bool {
+	return ap.hasPartIndex(index) && ap.dicts().StreetTypes.HasKey(ap.AddressStringParts[index]) &&
+		!ap.isPartString(index-1)
+}
+
+// check two address parts are in the same segment
+func (ap *AddressParts) sameSegment(index int, otherIndex int) bool {
+	return ap.partSegments == nil || ap.partSegments[index] == ap.partSegments[otherIndex]
+}
+
+// check an address part is in the last segment
+func (ap *AddressParts) isLastSegment(index int) bool {
+	return ap.partSegments == nil || ap.sameSegment(index, len(ap.partSegments)-1)
+}
+
+// last part of the segment of an address part that hasn't been removed
+func (ap *AddressParts) segmentEnd(index int) int {
+	endIndex := index
+	for i := index + 1; ap.hasPartIndex(i) && ap.sameSegment(index, i); i++ {
+		if ap.AddressStringParts[i] != "" {
+			endIndex = i
+		}
+	}
+	return endIndex
+}
+
+func (ap *AddressParts) isPartThe(index int) bool {
+	return ap.hasPartIndex(index) && ap.AddressStringParts[index] == "THE"
+}
+
+// a street number, not the number of a numbered street
+func (ap *AddressParts) isPartStreetNumber(index int) bool {
+	return ap.isPartAnyNumber(index) && !ap.isPartNumberedStreet(index)
+}
+
+// the number of a numbered street, eg. the 1 of HIGHWAY 1. the street
+// type can't be after a name, SMITH RD 12 is not a numbered street
+func (ap *AddressParts) isPartNumberedStreet(index int) bool {
+	if index < 1 || !ap.hasPartIndex(index) || !ap.isPartNumber(index) {
+		return false
+	}
+	return ap.dicts().NumberedStreetTypes.Has(ap.AddressStringParts[index-1]) && !ap.isPartString(index-2)
 }
 
 func (ap *AddressParts) isPartAny(_ int) bool {
 	return true
 }
 
+func (ap *AddressParts) isPartCornerConnector(index int) bool {
+	return ap.hasPartIndex(index) &&
+		(ap.AddressStringParts[index] == "&" || ap.AddressStringParts[index] == "AND")
+}
+
+// street type or a street suffix after a street type, eg. ST or ST N
+func (ap *AddressParts) isPartStreetTypeOrSuffix(index int) bool {
+	if ap.hasPartIndex(index) && ap.dicts().StreetSuffixes.HasKey(ap.AddressStringParts[index]) {
+		return ap.isPartStreetType(index - 1)
+	}
+	return ap.isPartStreetType(index)
+}
+
 func (ap *AddressParts) isPartStreetType(index int) bool {
-	if index < 0 {
+	if !ap.hasPartIndex(index) {
 		return false
 	}
-	result := fuzzyMatch(ap.AddressStringParts[index], streetTypes)
+	result, distance := ap.config().fuzzyMatch(ap.AddressStringParts[index], ap.dicts().StreetTypes)
+	ap.traceMatch(ap.AddressStringParts[index], result, distance)
 	return (len(result) > 0)
 }
 
@@ -447,7 +1255,7 @@ func (ap *AddressParts) isPartStreetType(index int) bool {
 func (ap *AddressParts) matchPostCode(index int) int {
 	var postCode int
 
-	if matched, _ := regexp.MatchString("^[0-9]{4}$", ap.AddressStringParts[index]); matched {
+	if addressPart := ap.AddressStringParts[index]; len(addressPart) == 4 && isDigits(addressPart) {
 		// found potential postcode
 		postCode, _ = strconv.Atoi(ap.AddressStringParts[index])
 		return postCode
@@ -460,6 +1268,7 @@ func (ap *AddressParts) findIndex(
 	startIndex int, current func(int) bool, last func(int) bool,
 ) int {
 	for index := startIndex; index < len(ap.AddressStringParts); index++ {
+		ap.traceExamined(index)
 		if current(index) && last(index-1) {
 			return index
 		}
@@ -471,6 +1280,7 @@ func (ap *AddressParts) findIndexReverse(
 	startIndex int, current func(int) bool, last func(int) bool,
 ) int {
 	for index := startIndex; index >= 0; index-- {
+		ap.traceExamined(index)
 		if current(index) && last(index+1) {
 			return index
 		}
@@ -24,7 +24,6 @@ var flatTypes = map[string]string{
 	"KSK":  "KIOSK",
 	"LBBY": "LOBBY",
 	"LOFT": "LOFT",
-	"LOT":  "LOT",
 	"LSE":  "LEASE",
 	"MBTH": "MARINE BERTH",
 	"MSNT": "MAISONETTE",
@@ -74,10 +73,41 @@ var levelTypes = map[string]string{
 	"UG":   "UPPER GROUND FLOOR",
 }
 
+var postalDeliveryTypes = map[string]string{
+	"CARE PO":     "CARE OF POST OFFICE",
+	"CMA":         "COMMUNITY MAIL AGENT",
+	"CMB":         "COMMUNITY MAIL BAG",
+	"CPA":         "COMMUNITY POSTAL AGENT",
+	"GPO BOX":     "GENERAL POST OFFICE BOX",
+	"LOCKED BAG":  "LOCKED MAIL BAG SERVICE",
+	"MS":          "MAIL SERVICE",
+	"PO BOX":      "POST OFFICE BOX",
+	"PRIVATE BAG": "PRIVATE MAIL BAG SERVICE",
+	"RMB":         "ROADSIDE MAIL BAG",
+	"RMS":         "ROADSIDE MAIL SERVICE",
+	"RSD":         "ROADSIDE DELIVERY",
+}
+
+// words before a lot number, eg. LOT 5 or LT 5
+var lotTypes = map[string]string{
+	"LOT": "LOT",
+	"LT":  "LOT",
+}
+
+// cadastral plans a lot is on, eg. LOT 5 DP 123456
+var planTypes = map[string]string{
+	"CP": "CROWN PLAN",
+	"DP": "DEPOSITED PLAN",
+	"LP": "LODGED PLAN",
+	"PS": "PLAN OF SUBDIVISION",
+	"SP": "STRATA PLAN",
+}
+
 var addressTypesNoNumber = map[string]string{
-	"LG": "LOWER GROUND FLOOR",
-	"UG": "UPPER GROUND FLOOR",
-	"FL": "FLOOR",
+	"LG":      "LOWER GROUND FLOOR",
+	"UG":      "UPPER GROUND FLOOR",
+	"FL":      "FLOOR",
+	"CARE PO": "CARE OF POST OFFICE",
 }
 
 var australianStates = map[string]string{
@@ -361,6 +391,30 @@ var streetTypes = map[string]string{
 	"MAZE":         "MZ",
 }
 
+// common abbreviations of street types that aren't the standard abbreviation,
+// eg. AVE for AV
+var streetTypeAliases = map[string]string{
+	"AVE":  "AVENUE",
+	"BLVD": "BOULEVARD",
+	"CRES": "CRESCENT",
+	"CRT":  "COURT",
+	"DRV":  "DRIVE",
+	"GRV":  "GROVE",
+	"LN":   "LANE",
+	"PKWY": "PARKWAY",
+	"TERR": "TERRACE",
+}
+
+// street types that are numbered rather than named, eg. HIGHWAY 1, ROUTE 66
+var numberedStreetTypes = map[string]string{
+	"EXPRESSWAY": "EXP",
+	"FREEWAY":    "FWY",
+	"HIGHWAY":    "HWY",
+	"MOTORWAY":   "MTWY",
+	"ROUTE":      "RTE",
+	"TOLLWAY":    "TLWY",
+}
+
 var streetSuffixes = map[string]string{
 	"CN": "CENTRAL",
 	"DE": "DEVIATION",
@@ -382,3 +436,28 @@ var streetSuffixes = map[string]string{
 	"OP": "OVERPASS",
 	"OT": "OUTER",
 }
+
+type postCodeRange struct {
+	State string
+	Start int
+	End   int
+}
+
+var postCodeRanges = []postCodeRange{
+	{"NSW", 1000, 1999},
+	{"NSW", 2000, 2599},
+	{"NSW", 2619, 2899},
+	{"NSW", 2921, 2999},
+	{"ACT", 200, 299},
+	{"ACT", 2600, 2618},
+	{"ACT", 2900, 2920},
+	{"VIC", 3000, 3999},
+	{"VIC", 8000, 8999},
+	{"QLD", 4000, 4999},
+	{"QLD", 9000, 9999},
+	{"SA", 5000, 5999},
+	{"WA", 6000, 6797},
+	{"WA", 6800, 6999},
+	{"TAS", 7000, 7999},
+	{"NT", 800, 999},
+}
@@ -1,11 +1,15 @@
 package addressparser
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 )
 
+// dump the address fields rather than the formatted address
+var spewConfig = spew.ConfigState{Indent: " ", DisableMethods: true}
+
 var testAddresses = []AddressParts{
 	{
 		AddressString:      "123 WESTLING HWY",
@@ -82,7 +86,7 @@ var testAddresses = []AddressParts{
 		StreetNumberEnd:    0,
 		StreetNumberSuffix: "",
 		StreetName:         "THE BOULEVARDE",
-		StreetType:         "-",
+		NoStreetType:       true,
 		Suburb:             "FLAT OAK",
 		PostCode:           2529,
 		State:              "NSW",
@@ -123,8 +127,9 @@ var testAddresses = []AddressParts{
 		AddressString:      "LOT 374 WEST ST, ASCOT PARK SA 5043",
 		LevelType:          "",
 		LevelNumber:        0,
-		FlatType:           "LOT",
-		FlatNumber:         374,
+		FlatType:           "",
+		FlatNumber:         0,
+		LotNumber:          374,
 		FlatNumberSuffix:   "",
 		StreetNumber:       0,
 		StreetNumberEnd:    0,
@@ -203,8 +208,9 @@ var testAddresses = []AddressParts{
 		AddressString:      "Lot 11, 75 Scotts Head Road, Way Way, NSW 2447",
 		LevelType:          "",
 		LevelNumber:        0,
-		FlatType:           "LOT",
-		FlatNumber:         11,
+		FlatType:           "",
+		FlatNumber:         0,
+		LotNumber:          11,
 		FlatNumberSuffix:   "",
 		StreetNumber:       75,
 		StreetNumberEnd:    0,
@@ -247,6 +253,359 @@ var testAddresses = []AddressParts{
 		PostCode:           3000,
 		State:              "VIC",
 	},
+	{
+		AddressString: "3/123 Smith St Richmond VIC 3121",
+		FlatNumber:    3,
+		StreetNumber:  123,
+		StreetName:    "SMITH",
+		StreetType:    "STREET",
+		Suburb:        "RICHMOND",
+		PostCode:      3121,
+		State:         "VIC",
+	},
+	{
+		AddressString:    "3A/12-14 Smith St, Richmond VIC 3121",
+		FlatNumber:       3,
+		FlatNumberSuffix: "A",
+		StreetNumber:     12,
+		StreetNumberEnd:  14,
+		StreetName:       "SMITH",
+		StreetType:       "STREET",
+		Suburb:           "RICHMOND",
+		PostCode:         3121,
+		State:            "VIC",
+	},
+	{
+		AddressString:      "Unit 4 / 56B Smith St Richmond VIC 3121",
+		FlatType:           "UNIT",
+		FlatNumber:         4,
+		StreetNumber:       56,
+		StreetNumberSuffix: "B",
+		StreetName:         "SMITH",
+		StreetType:         "STREET",
+		Suburb:             "RICHMOND",
+		PostCode:           3121,
+		State:              "VIC",
+	},
+	{
+		AddressString:        "PO Box 123 Fitzroy VIC 3065",
+		Suburb:               "FITZROY",
+		PostCode:             3065,
+		State:                "VIC",
+		PostalDeliveryType:   "PO BOX",
+		PostalDeliveryNumber: 123,
+	},
+	{
+		AddressString:        "Locked Bag 4000 Sydney NSW 2001",
+		Suburb:               "SYDNEY",
+		PostCode:             2001,
+		State:                "NSW",
+		PostalDeliveryType:   "LOCKED BAG",
+		PostalDeliveryNumber: 4000,
+	},
+	{
+		AddressString:              "G.P.O. Box A12 Melbourne VIC 3001",
+		Suburb:                     "MELBOURNE",
+		PostCode:                   3001,
+		State:                      "VIC",
+		PostalDeliveryType:         "GPO BOX",
+		PostalDeliveryNumber:       12,
+		PostalDeliveryNumberPrefix: "A",
+	},
+	{
+		AddressString:        "RMB 1234 Wangaratta VIC 3677",
+		Suburb:               "WANGARATTA",
+		PostCode:             3677,
+		State:                "VIC",
+		PostalDeliveryType:   "RMB",
+		PostalDeliveryNumber: 1234,
+	},
+	{
+		AddressString:      "Care PO Alice Springs NT 0870",
+		Suburb:             "ALICE SPRINGS",
+		PostCode:           870,
+		State:              "NT",
+		PostalDeliveryType: "CARE PO",
+	},
+	{
+		AddressString:    "Cnr Smith St and Jones Rd, Fitzroy VIC 3065",
+		StreetName:       "SMITH",
+		StreetType:       "STREET",
+		Suburb:           "FITZROY",
+		PostCode:         3065,
+		State:            "VIC",
+		CornerStreetName: "JONES",
+		CornerStreetType: "ROAD",
+	},
+	{
+		AddressString:      "Shop 2 Corner of High St & Station St N Kew VIC 3101",
+		FlatType:           "SHOP",
+		FlatNumber:         2,
+		StreetName:         "HIGH",
+		StreetType:         "STREET",
+		Suburb:             "KEW",
+		PostCode:           3101,
+		State:              "VIC",
+		CornerStreetName:   "STATION",
+		CornerStreetType:   "STREET",
+		CornerStreetSuffix: "N",
+	},
+	{
+		AddressString: "Suite 4, Rialto Towers, 525 Collins St Melbourne",
+		FlatType:      "SE",
+		FlatNumber:    4,
+		BuildingName:  "RIALTO TOWERS",
+		StreetNumber:  525,
+		StreetName:    "COLLINS",
+		StreetType:    "STREET",
+		Suburb:        "MELBOURNE",
+	},
+	{
+		AddressString: "Westfield Doncaster Shop 12 619 Doncaster Rd Doncaster VIC 3108",
+		FlatType:      "SHOP",
+		FlatNumber:    12,
+		BuildingName:  "WESTFIELD DONCASTER",
+		StreetNumber:  619,
+		StreetName:    "DONCASTER",
+		StreetType:    "ROAD",
+		Suburb:        "DONCASTER",
+		PostCode:      3108,
+		State:         "VIC",
+	},
+	{
+		AddressString: "Unit 3 Rose Court 12 Smith St Richmond VIC 3121",
+		FlatType:      "UNIT",
+		FlatNumber:    3,
+		BuildingName:  "ROSE COURT",
+		StreetNumber:  12,
+		StreetName:    "SMITH",
+		StreetType:    "STREET",
+		Suburb:        "RICHMOND",
+		PostCode:      3121,
+		State:         "VIC",
+	},
+	{
+		AddressString: "Lot 5 DP 123456 Smith Rd Wagga Wagga NSW 2650",
+		LotNumber:     5,
+		PlanType:      "DP",
+		PlanNumber:    "123456",
+		StreetName:    "SMITH",
+		StreetType:    "ROAD",
+		Suburb:        "WAGGA WAGGA",
+		PostCode:      2650,
+		State:         "NSW",
+	},
+	{
+		AddressString: "Lot 3 on SP 12345, Jones Rd Gympie QLD 4570",
+		LotNumber:     3,
+		PlanType:      "SP",
+		PlanNumber:    "12345",
+		StreetName:    "JONES",
+		StreetType:    "ROAD",
+		Suburb:        "GYMPIE",
+		PostCode:      4570,
+		State:         "QLD",
+	},
+	{
+		AddressString:   "Lot 12A Plan of Subdivision 654321B Hill Rd Kyneton VIC 3444",
+		LotNumber:       12,
+		LotNumberSuffix: "A",
+		PlanType:        "PS",
+		PlanNumber:      "654321B",
+		StreetName:      "HILL",
+		StreetType:      "ROAD",
+		Suburb:          "KYNETON",
+		PostCode:        3444,
+		State:           "VIC",
+	},
+	{
+		AddressString: "Lt 7 DP 24680 Hill Rd Kyneton VIC 3444",
+		LotNumber:     7,
+		PlanType:      "DP",
+		PlanNumber:    "24680",
+		StreetName:    "HILL",
+		StreetType:    "ROAD",
+		Suburb:        "KYNETON",
+		PostCode:      3444,
+		State:         "VIC",
+	},
+	{
+		AddressString: "Smith & Sons St Fitzroy VIC 3065",
+		StreetName:    "SMITH SONS",
+		StreetType:    "STREET",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 Park Lane Rd Fitzroy VIC 3065",
+		StreetNumber:  12,
+		StreetName:    "PARK LANE",
+		StreetType:    "ROAD",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "5 Grove Parade St VIC 3065",
+		StreetNumber:  5,
+		StreetName:    "GROVE PARADE",
+		StreetType:    "STREET",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "The Esplanade St Kilda VIC 3182",
+		StreetName:    "THE ESPLANADE",
+		NoStreetType:  true,
+		Suburb:        "ST KILDA",
+		PostCode:      3182,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 Esplanade St Kilda VIC 3182",
+		StreetNumber:  12,
+		StreetName:    "ESPLANADE",
+		NoStreetType:  true,
+		Suburb:        "ST KILDA",
+		PostCode:      3182,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 Smith Esplanade St Kilda VIC 3182",
+		StreetNumber:  12,
+		StreetName:    "SMITH",
+		StreetType:    "ESPLANADE",
+		Suburb:        "ST KILDA",
+		PostCode:      3182,
+		State:         "VIC",
+	},
+	{
+		AddressString: "4 The Briars Fitzroy VIC 3065",
+		StreetNumber:  4,
+		StreetName:    "THE BRIARS",
+		NoStreetType:  true,
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 Grove Parade St, Fitzroy VIC 3065",
+		StreetNumber:  12,
+		StreetName:    "GROVE PARADE",
+		StreetType:    "STREET",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 Smith St North, Fitzroy VIC 3065",
+		StreetNumber:  12,
+		StreetName:    "SMITH",
+		StreetType:    "STREET",
+		StreetSuffix:  "N",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "Rialto Towers, Collins St, Melbourne VIC 3000",
+		BuildingName:  "RIALTO TOWERS",
+		StreetName:    "COLLINS",
+		StreetType:    "STREET",
+		Suburb:        "MELBOURNE",
+		PostCode:      3000,
+		State:         "VIC",
+	},
+	{
+		AddressString: "Rialto Towers\n525 Collins St\nMelbourne VIC 3000",
+		BuildingName:  "RIALTO TOWERS",
+		StreetNumber:  525,
+		StreetName:    "COLLINS",
+		StreetType:    "STREET",
+		Suburb:        "MELBOURNE",
+		PostCode:      3000,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 O’Connor St Hall's Creek WA 6770",
+		StreetNumber:  12,
+		StreetName:    "O'CONNOR",
+		StreetType:    "STREET",
+		Suburb:        "HALL'S CREEK",
+		PostCode:      6770,
+		State:         "WA",
+	},
+	{
+		AddressString: "5 D'Arcy-Irvine St Bel-Air SA 5052",
+		StreetNumber:  5,
+		StreetName:    "D'ARCY-IRVINE",
+		StreetType:    "STREET",
+		Suburb:        "BEL-AIR",
+		PostCode:      5052,
+		State:         "SA",
+	},
+	{
+		AddressString: "123 3rd Av Mount Lawley WA 6050",
+		StreetNumber:  123,
+		StreetName:    "3RD",
+		StreetType:    "AVENUE",
+		Suburb:        "MOUNT LAWLEY",
+		PostCode:      6050,
+		State:         "WA",
+	},
+	{
+		AddressString: "123 3RD AVE Fitzroy",
+		StreetNumber:  123,
+		StreetName:    "3RD",
+		StreetType:    "AVENUE",
+		Suburb:        "FITZROY",
+	},
+	{
+		AddressString: "Unit 3 22nd St Fitzroy VIC 3065",
+		FlatType:      "UNIT",
+		FlatNumber:    3,
+		StreetName:    "22ND",
+		StreetType:    "STREET",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "12 11th St Fitzroy VIC 3065",
+		StreetNumber:  12,
+		StreetName:    "11TH",
+		StreetType:    "STREET",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "5 First-Second St Fitzroy VIC 3065",
+		StreetNumber:  5,
+		StreetName:    "FIRST-SECOND",
+		StreetType:    "STREET",
+		Suburb:        "FITZROY",
+		PostCode:      3065,
+		State:         "VIC",
+	},
+	{
+		AddressString: "1200 Route 66 Springfield QLD 4300",
+		StreetNumber:  1200,
+		StreetName:    "ROUTE 66",
+		NoStreetType:  true,
+		Suburb:        "SPRINGFIELD",
+		PostCode:      4300,
+		State:         "QLD",
+	},
+	{
+		AddressString: "Hwy 1 Glenrowan VIC 3675",
+		StreetName:    "HIGHWAY 1",
+		NoStreetType:  true,
+		Suburb:        "GLENROWAN",
+		PostCode:      3675,
+		State:         "VIC",
+	},
 }
 
 func errorExpectedString(t *testing.T, expected string, actual string) {
@@ -267,7 +626,7 @@ func TestAddress(t *testing.T) {
 	//addressParts.LoadAddressString("UNIT 1 2 LITTLE HILL STREET TWEED HEADS NSW 2485")
 	addressParts.ProcessAddress()
 
-	spew.Dump(addressParts)
+	spewConfig.Dump(addressParts)
 }
 
 func TestAddresses(t *testing.T) {
@@ -277,70 +636,213 @@ func TestAddresses(t *testing.T) {
 		addressParts.LoadAddressString(testAddress.AddressString)
 		addressParts.ProcessAddress()
 
-		var hasError bool
-		if testAddress.LevelType != addressParts.LevelType {
-			hasError = true
-			errorExpectedString(t, testAddress.LevelType, addressParts.LevelType)
-		}
-		if testAddress.LevelNumber != addressParts.LevelNumber {
-			hasError = true
-			errorExpectedInt(t, testAddress.LevelNumber, addressParts.LevelNumber)
-		}
-		if testAddress.FlatType != addressParts.FlatType {
-			hasError = true
-			errorExpectedString(t, testAddress.FlatType, addressParts.FlatType)
-		}
-		if testAddress.FlatNumber != addressParts.FlatNumber {
-			hasError = true
-			errorExpectedInt(t, testAddress.FlatNumber, addressParts.FlatNumber)
-		}
-		if testAddress.FlatNumberSuffix != addressParts.FlatNumberSuffix {
-			hasError = true
-			errorExpectedString(t, testAddress.FlatNumberSuffix, addressParts.FlatNumberSuffix)
-		}
-		if testAddress.StreetNumber != addressParts.StreetNumber {
-			hasError = true
-			errorExpectedInt(t, testAddress.StreetNumber, addressParts.StreetNumber)
-		}
-		if testAddress.StreetNumberEnd != addressParts.StreetNumberEnd {
-			hasError = true
-			errorExpectedInt(t, testAddress.StreetNumberEnd, addressParts.StreetNumberEnd)
-		}
-		if testAddress.StreetNumberSuffix != addressParts.StreetNumberSuffix {
-			hasError = true
-			errorExpectedString(t, testAddress.StreetNumberSuffix, addressParts.StreetNumberSuffix)
-		}
-		if testAddress.StreetName != addressParts.StreetName {
-			hasError = true
-			errorExpectedString(t, testAddress.StreetName, addressParts.StreetName)
-		}
-		if testAddress.StreetType != addressParts.StreetType {
-			hasError = true
-			errorExpectedString(t, testAddress.StreetType, addressParts.StreetType)
-		}
-		if testAddress.Suburb != addressParts.Suburb {
-			hasError = true
-			errorExpectedString(t, testAddress.Suburb, addressParts.Suburb)
-		}
-		if testAddress.PostCode != addressParts.PostCode {
-			hasError = true
-			errorExpectedInt(t, testAddress.PostCode, addressParts.PostCode)
+		if !checkAddress(t, testAddress, &addressParts) {
+			spewConfig.Dump(addressParts)
 		}
-		if testAddress.State != addressParts.State {
+	}
+}
+
+// check the parsed fields of an address are the expected, reporting each difference
+func checkAddress(t *testing.T, testAddress AddressParts, addressParts *AddressParts) bool {
+	var hasError bool
+	if testAddress.LevelType != addressParts.LevelType {
+		hasError = true
+		errorExpectedString(t, testAddress.LevelType, addressParts.LevelType)
+	}
+	if testAddress.LevelNumber != addressParts.LevelNumber {
+		hasError = true
+		errorExpectedInt(t, testAddress.LevelNumber, addressParts.LevelNumber)
+	}
+	if testAddress.FlatType != addressParts.FlatType {
+		hasError = true
+		errorExpectedString(t, testAddress.FlatType, addressParts.FlatType)
+	}
+	if testAddress.FlatNumber != addressParts.FlatNumber {
+		hasError = true
+		errorExpectedInt(t, testAddress.FlatNumber, addressParts.FlatNumber)
+	}
+	if testAddress.FlatNumberSuffix != addressParts.FlatNumberSuffix {
+		hasError = true
+		errorExpectedString(t, testAddress.FlatNumberSuffix, addressParts.FlatNumberSuffix)
+	}
+	if testAddress.StreetNumber != addressParts.StreetNumber {
+		hasError = true
+		errorExpectedInt(t, testAddress.StreetNumber, addressParts.StreetNumber)
+	}
+	if testAddress.StreetNumberEnd != addressParts.StreetNumberEnd {
+		hasError = true
+		errorExpectedInt(t, testAddress.StreetNumberEnd, addressParts.StreetNumberEnd)
+	}
+	if testAddress.StreetNumberSuffix != addressParts.StreetNumberSuffix {
+		hasError = true
+		errorExpectedString(t, testAddress.StreetNumberSuffix, addressParts.StreetNumberSuffix)
+	}
+	if testAddress.StreetName != addressParts.StreetName {
+		hasError = true
+		errorExpectedString(t, testAddress.StreetName, addressParts.StreetName)
+	}
+	if testAddress.StreetType != addressParts.StreetType {
+		hasError = true
+		errorExpectedString(t, testAddress.StreetType, addressParts.StreetType)
+	}
+	if testAddress.StreetSuffix != addressParts.StreetSuffix {
+		hasError = true
+		errorExpectedString(t, testAddress.StreetSuffix, addressParts.StreetSuffix)
+	}
+	if testAddress.NoStreetType != addressParts.NoStreetType {
+		hasError = true
+		t.Errorf("expected NoStreetType %t, actual %t", testAddress.NoStreetType, addressParts.NoStreetType)
+	}
+	if testAddress.Suburb != addressParts.Suburb {
+		hasError = true
+		errorExpectedString(t, testAddress.Suburb, addressParts.Suburb)
+	}
+	if testAddress.PostCode != addressParts.PostCode {
+		hasError = true
+		errorExpectedInt(t, testAddress.PostCode, addressParts.PostCode)
+	}
+	if testAddress.State != addressParts.State {
+		hasError = true
+		errorExpectedString(t, testAddress.State, addressParts.State)
+	}
+	if testAddress.PostalDeliveryType != addressParts.PostalDeliveryType {
+		hasError = true
+		errorExpectedString(t, testAddress.PostalDeliveryType, addressParts.PostalDeliveryType)
+	}
+	if testAddress.PostalDeliveryNumber != addressParts.PostalDeliveryNumber {
+		hasError = true
+		errorExpectedInt(t, testAddress.PostalDeliveryNumber, addressParts.PostalDeliveryNumber)
+	}
+	if testAddress.PostalDeliveryNumberPrefix != addressParts.PostalDeliveryNumberPrefix {
+		hasError = true
+		errorExpectedString(t, testAddress.PostalDeliveryNumberPrefix, addressParts.PostalDeliveryNumberPrefix)
+	}
+	if testAddress.PostalDeliveryNumberSuffix != addressParts.PostalDeliveryNumberSuffix {
+		hasError = true
+		errorExpectedString(t, testAddress.PostalDeliveryNumberSuffix, addressParts.PostalDeliveryNumberSuffix)
+	}
+	if testAddress.LotNumber != addressParts.LotNumber {
+		hasError = true
+		errorExpectedInt(t, testAddress.LotNumber, addressParts.LotNumber)
+	}
+	if testAddress.LotNumberSuffix != addressParts.LotNumberSuffix {
+		hasError = true
+		errorExpectedString(t, testAddress.LotNumberSuffix, addressParts.LotNumberSuffix)
+	}
+	if testAddress.PlanType != addressParts.PlanType {
+		hasError = true
+		errorExpectedString(t, testAddress.PlanType, addressParts.PlanType)
+	}
+	if testAddress.PlanNumber != addressParts.PlanNumber {
+		hasError = true
+		errorExpectedString(t, testAddress.PlanNumber, addressParts.PlanNumber)
+	}
+	if testAddress.BuildingName != addressParts.BuildingName {
+		hasError = true
+		errorExpectedString(t, testAddress.BuildingName, addressParts.BuildingName)
+	}
+	if testAddress.CornerStreetName != addressParts.CornerStreetName {
+		hasError = true
+		errorExpectedString(t, testAddress.CornerStreetName, addressParts.CornerStreetName)
+	}
+	if testAddress.CornerStreetType != addressParts.CornerStreetType {
+		hasError = true
+		errorExpectedString(t, testAddress.CornerStreetType, addressParts.CornerStreetType)
+	}
+	if testAddress.CornerStreetSuffix != addressParts.CornerStreetSuffix {
+		hasError = true
+		errorExpectedString(t, testAddress.CornerStreetSuffix, addressParts.CornerStreetSuffix)
+	}
+	for _, val := range addressParts.AddressStringParts {
+		if val != "" {
 			hasError = true
-			errorExpectedString(t, testAddress.State, addressParts.State)
+			t.Errorf("Address Leftovers: %s", val)
 		}
-		for _, val := range addressParts.AddressStringParts {
-			if val != "" {
-				hasError = true
-				t.Errorf("Address Leftovers: %s", val)
-			}
+	}
+
+	return !hasError
+}
+
+func TestSpans(t *testing.T) {
+	address := "Unit 3a, 12-14 O'Connor st, St. Kilda vic 3182"
+	addressParts, err := NewAddress(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"FlatType":     "Unit",
+		"FlatNumber":   "3a",
+		"StreetNumber": "12-14",
+		"StreetName":   "O'Connor",
+		"StreetType":   "st",
+		"Suburb":       "St. Kilda",
+		"State":        "vic",
+		"PostCode":     "3182",
+	}
+	for field, original := range expected {
+		if addressParts.Original(field) != original {
+			errorExpectedString(t, original, addressParts.Original(field))
 		}
+	}
 
-		if hasError {
-			spew.Dump(addressParts)
+	span := addressParts.Spans["StreetName"]
+	if address[span.Start:span.End] != "O'Connor" {
+		t.Errorf("unexpected StreetName span %v", span)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	// a label with the recipient on the first line
+	addressParts, err := NewAddress("John Smith\nRialto Towers\n525 Collins St\nMelbourne VIC 3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "RIALTO TOWERS" {
+		errorExpectedString(t, "RIALTO TOWERS", addressParts.BuildingName)
+	}
+	parseError, ok := addressParts.Check().(*ParseError)
+	if !ok || parseError.Kind != ErrLeftoverParts || strings.Join(parseError.Parts, " ") != "JOHN SMITH" {
+		t.Errorf("expected JOHN SMITH left over, actual %v", addressParts.Check())
+	}
+}
+
+func BenchmarkNewAddress(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testAddress := testAddresses[i%len(testAddresses)]
+		if _, err := NewAddress(testAddress.AddressString); err != nil {
+			b.Fatal(err)
 		}
+	}
+}
 
+func BenchmarkFuzzyMatch(b *testing.B) {
+	tokens := []string{"ST", "STRET", "HELLO", "MELBOURNE", "VIC", "UNIT"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		fuzzyMatch(tokens[i%len(tokens)], defaultDictionaries.StreetTypes, FuzzySearch{}, FuzzySearchThreshold)
+	}
+}
+
+func TestBuildingNameGuess(t *testing.T) {
+	// words before the street on the same line, not next to a flat or level, are left over
+	addressParts, err := NewAddress("John Smith 12 Smith St Richmond VIC 3121")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "" {
+		errorExpectedString(t, "", addressParts.BuildingName)
+	}
+	if !IsParseError(addressParts.Check(), ErrLeftoverParts) {
+		t.Errorf("expected JOHN SMITH left over, actual %v", addressParts.Check())
 	}
 
+	addressParts, err = NewAddress("Level 2 Rialto Towers 525 Collins St Melbourne VIC 3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "RIALTO TOWERS" {
+		errorExpectedString(t, "RIALTO TOWERS", addressParts.BuildingName)
+	}
 }
@@ -0,0 +1,229 @@
+package addressparser
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CSVOptions - options for parsing addresses in a csv file
+type CSVOptions struct {
+	// header names of the columns joined to make the address, default "address"
+	Columns []string
+	// rows that fail to parse are written here as csv instead of the output
+	Rejects io.Writer
+}
+
+// ParseCSV parse addresses from a csv with a header row, writing the original
+// columns plus a column for each of FieldNames and a ParseError column.
+// rows are streamed so the csv is never loaded into memory
+func ParseCSV(reader io.Reader, writer io.Writer, options CSVOptions) error {
+	return defaultParser.ParseCSV(reader, writer, options)
+}
+
+// ParseCSV parse the address columns of a csv with the parser, see ParseCSV
+func (p *Parser) ParseCSV(reader io.Reader, writer io.Writer, options CSVOptions) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	csvWriter := csv.NewWriter(writer)
+
+	var rejectWriter *csv.Writer
+	if options.Rejects != nil {
+		rejectWriter = csv.NewWriter(options.Rejects)
+	}
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to read csv header")
+	}
+	columnIndexes, err := csvColumnIndexes(header, options.Columns)
+	if err != nil {
+		return err
+	}
+
+	outputHeader := append(append([]string{}, header...), FieldNames...)
+	if err := csvWriter.Write(append(outputHeader, "ParseError")); err != nil {
+		return errors.Wrap(err, "Failed to write csv")
+	}
+	if rejectWriter != nil {
+		if err := rejectWriter.Write(header); err != nil {
+			return errors.Wrap(err, "Failed to write rejects csv")
+		}
+	}
+
+	emptyFields := make([]string, len(FieldNames))
+	for line := 2; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to read csv line %d", line)
+		}
+
+		var addressColumns []string
+		for _, index := range columnIndexes {
+			if index < len(record) && strings.TrimSpace(record[index]) != "" {
+				addressColumns = append(addressColumns, strings.TrimSpace(record[index]))
+			}
+		}
+		address := strings.Join(addressColumns, ", ")
+
+		addressParts, err := p.Parse(address)
+
+		if err != nil && rejectWriter != nil {
+			if err := rejectWriter.Write(record); err != nil {
+				return errors.Wrap(err, "Failed to write rejects csv")
+			}
+			continue
+		}
+
+		output := append([]string{}, record...)
+		if err != nil {
+			output = append(append(output, emptyFields...), err.Error())
+		} else {
+			output = append(append(output, addressParts.FieldValues()...), "")
+		}
+		if err := csvWriter.Write(output); err != nil {
+			return errors.Wrap(err, "Failed to write csv")
+		}
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return errors.Wrap(err, "Failed to write csv")
+	}
+	if rejectWriter != nil {
+		rejectWriter.Flush()
+		if err := rejectWriter.Error(); err != nil {
+			return errors.Wrap(err, "Failed to write rejects csv")
+		}
+	}
+
+	return nil
+}
+
+// find the index of each column name in the header, ignoring case
+func csvColumnIndexes(header []string, columns []string) ([]int, error) {
+	if len(columns) == 0 {
+		columns = []string{"address"}
+	}
+
+	var columnIndexes []int
+	for _, column := range columns {
+		found := false
+		for index, name := range header {
+			if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+				columnIndexes = append(columnIndexes, index)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("Column %s not found in csv header", column)
+		}
+	}
+	return columnIndexes, nil
+}
+
+// BatchResult - result of parsing an address in a batch
+type BatchResult struct {
+	// position of the address in the batch input
+	Index   int
+	Input   string
+	Address *AddressParts
+	Err     error
+}
+
+// ParseBatch parse addresses from a channel with a pool of workers, results
+// are returned in the same order as the input. workers defaults to the number
+// of CPUs. the results channel is closed when the input channel is closed and
+// all addresses are parsed, or as soon as the context is cancelled.
+func ParseBatch(ctx context.Context, addresses <-chan string, workers int) <-chan BatchResult {
+	return defaultParser.ParseBatch(ctx, addresses, workers)
+}
+
+// ParseBatch parse addresses from a channel with the parser, see ParseBatch
+func (p *Parser) ParseBatch(ctx context.Context, addresses <-chan string, workers int) <-chan BatchResult {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	type batchJob struct {
+		index  int
+		input  string
+		result chan BatchResult
+	}
+	jobs := make(chan batchJob)
+	// result channel of each job in input order
+	pending := make(chan chan BatchResult, workers)
+	results := make(chan BatchResult)
+
+	go func() {
+		defer close(jobs)
+		defer close(pending)
+		for index := 0; ; index++ {
+			var input string
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case input, ok = <-addresses:
+				if !ok {
+					return
+				}
+			}
+
+			job := batchJob{index: index, input: input, result: make(chan BatchResult, 1)}
+			select {
+			case <-ctx.Done():
+				return
+			case pending <- job.result:
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case jobs <- job:
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range jobs {
+				addressParts, err := p.Parse(job.input)
+				job.result <- BatchResult{
+					Index:   job.index,
+					Input:   job.input,
+					Address: addressParts,
+					Err:     err,
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(results)
+		for result := range pending {
+			select {
+			case <-ctx.Done():
+				return
+			case batchResult := <-result:
+				select {
+				case <-ctx.Done():
+					return
+				case results <- batchResult:
+				}
+			}
+		}
+	}()
+
+	return results
+}
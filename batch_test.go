@@ -0,0 +1,141 @@
+package addressparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBatchCSV = `id,address1,address2,city
+1,Unit 3,5-11 Flathead Rd,Ettalong Beach NSW 2257
+2,,123 Highway St,Park Avenue QLD 4701
+3,,Smith,
+`
+
+func TestParseCSV(t *testing.T) {
+	var output, rejects bytes.Buffer
+	err := ParseCSV(strings.NewReader(testBatchCSV), &output, CSVOptions{
+		Columns: []string{"address1", "Address2", "city"},
+		Rejects: &rejects,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&output).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected 3 rows, actual %d", len(records))
+	}
+	header := records[0]
+	if len(header) != 4+len(FieldNames)+1 || header[4] != "FlatType" {
+		t.Errorf("unexpected header %v", header)
+	}
+
+	row := make(map[string]string)
+	for i, value := range records[1] {
+		row[header[i]] = value
+	}
+	expected := map[string]string{
+		"id":           "1",
+		"FlatType":     "UNIT",
+		"FlatNumber":   "3",
+		"StreetNumber": "5",
+		"StreetName":   "FLATHEAD",
+		"Suburb":       "ETTALONG BEACH",
+		"PostCode":     "2257",
+	}
+	for field, value := range expected {
+		if row[field] != value {
+			errorExpectedString(t, value, row[field])
+		}
+	}
+
+	if rejects.String() != "id,address1,address2,city\n3,,Smith,\n" {
+		t.Errorf("unexpected rejects %q", rejects.String())
+	}
+}
+
+func TestParseCSVMissingColumn(t *testing.T) {
+	var output bytes.Buffer
+	err := ParseCSV(strings.NewReader(testBatchCSV), &output, CSVOptions{})
+	if err == nil {
+		t.Error("expected missing column error")
+	}
+}
+
+func TestParseBatch(t *testing.T) {
+	inputs := []string{
+		"1234 hello st Melbourne VIC 3000",
+		"Smith",
+		"UNIT 3 5-11 FLATHEAD RD, ETTALONG BEACH NSW 2257",
+		"PO Box 123 Fitzroy VIC 3065",
+	}
+
+	addresses := make(chan string)
+	go func() {
+		defer close(addresses)
+		for i := 0; i < 100; i++ {
+			addresses <- inputs[i%len(inputs)]
+		}
+	}()
+
+	var count int
+	for result := range ParseBatch(context.Background(), addresses, 4) {
+		if result.Index != count {
+			errorExpectedInt(t, count, result.Index)
+		}
+		if result.Input != inputs[count%len(inputs)] {
+			errorExpectedString(t, inputs[count%len(inputs)], result.Input)
+		}
+		if result.Input == "Smith" && !IsParseError(result.Err, ErrAddressTooShort) {
+			t.Errorf("expected address too short error, actual %v", result.Err)
+		}
+		if result.Input != "Smith" && result.Err != nil {
+			t.Errorf("unexpected error %s", result.Err)
+		}
+		count++
+	}
+	if count != 100 {
+		errorExpectedInt(t, 100, count)
+	}
+}
+
+func TestParseBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// input is never closed, cancelling must close the results
+	addresses := make(chan string)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case addresses <- "1234 hello st Melbourne VIC 3000":
+			}
+		}
+	}()
+
+	results := ParseBatch(ctx, addresses, 2)
+	for i := 0; i < 10; i++ {
+		<-results
+	}
+	cancel()
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-results:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("results not closed after cancel")
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package addressparser
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maximum number of alternative parses to try
+const maxCandidates = 5
+
+// each street type skipped for an alternative parse lowers the confidence
+const skipPenalty = 0.9
+
+// Candidate - a possible parse of an address with its confidence
+type Candidate struct {
+	Address     *AddressParts
+	Confidence  float64
+	FieldScores map[string]float64
+}
+
+// ParseCandidates parse an address string into candidate parses,
+// ordered by confidence highest first
+func ParseCandidates(address string) ([]*Candidate, error) {
+	return defaultParser.ParseCandidates(address)
+}
+
+// ParseCandidates parse an address string into candidate parses,
+// ordered by confidence highest first
+func (p *Parser) ParseCandidates(address string) ([]*Candidate, error) {
+	var candidates []*Candidate
+
+	for skip := 0; skip < maxCandidates; skip++ {
+		addressParts := &AddressParts{parser: p}
+		err := addressParts.LoadAddressString(address)
+		if err != nil {
+			return candidates, err
+		}
+		addressParts.skipStreetTypes = skip
+		addressParts.ProcessAddress()
+		for _, step := range p.enrichSteps {
+			step(addressParts)
+		}
+
+		if skip > 0 && addressParts.StreetType == "" && !addressParts.NoStreetType {
+			// no more street types to skip
+			break
+		}
+		if hasCandidate(candidates, addressParts) {
+			continue
+		}
+		candidate := newCandidate(addressParts)
+		candidate.Confidence *= math.Pow(skipPenalty, float64(skip))
+		candidates = append(candidates, candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+
+	return candidates, nil
+}
+
+func newCandidate(addressParts *AddressParts) *Candidate {
+	candidate := &Candidate{
+		Address:     addressParts,
+		FieldScores: make(map[string]float64),
+	}
+
+	// sum in field order so equal parses always get the same confidence
+	fields := make([]string, 0, len(addressParts.fieldScores))
+	for field := range addressParts.fieldScores {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var totalScore float64
+	for _, field := range fields {
+		candidate.FieldScores[field] = addressParts.fieldScores[field]
+		totalScore += addressParts.fieldScores[field]
+	}
+	if len(candidate.FieldScores) == 0 {
+		return candidate
+	}
+	candidate.Confidence = totalScore / float64(len(candidate.FieldScores))
+
+	// leftover parts lower the confidence
+	partCount := len(strings.Fields(addressParts.AddressString))
+	var leftoverCount int
+	for _, addressPart := range addressParts.AddressStringParts {
+		if addressPart != "" {
+			leftoverCount++
+		}
+	}
+	if partCount > 0 {
+		candidate.Confidence *= float64(partCount-leftoverCount) / float64(partCount)
+	}
+
+	return candidate
+}
+
+func hasCandidate(candidates []*Candidate, addressParts *AddressParts) bool {
+	for _, candidate := range candidates {
+		if sameAddress(candidate.Address, addressParts) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare the parsed fields of two addresses
+func sameAddress(first *AddressParts, second *AddressParts) bool {
+	a, b := *first, *second
+	a.fieldScores, b.fieldScores = nil, nil
+	a.skipStreetTypes, b.skipStreetTypes = 0, 0
+	a.parser, b.parser = nil, nil
+	a.Trace, b.Trace = nil, nil
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,66 @@
+package addressparser
+
+import (
+	"testing"
+)
+
+func TestParseCandidates(t *testing.T) {
+	candidates, err := ParseCandidates("123 Butcher St St Fakeburb QLD 4568")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) < 2 {
+		t.Fatalf("expected at least 2 candidates, actual %d", len(candidates))
+	}
+
+	first := candidates[0].Address
+	if first.StreetName != "BUTCHER" {
+		errorExpectedString(t, "BUTCHER", first.StreetName)
+	}
+	if first.Suburb != "ST FAKEBURB" {
+		errorExpectedString(t, "ST FAKEBURB", first.Suburb)
+	}
+
+	second := candidates[1].Address
+	if second.StreetName != "BUTCHER ST" {
+		errorExpectedString(t, "BUTCHER ST", second.StreetName)
+	}
+	if second.Suburb != "FAKEBURB" {
+		errorExpectedString(t, "FAKEBURB", second.Suburb)
+	}
+
+	for i := 1; i < len(candidates); i++ {
+		if candidates[i].Confidence > candidates[i-1].Confidence {
+			t.Errorf("candidates not ordered by confidence: %f > %f",
+				candidates[i].Confidence, candidates[i-1].Confidence)
+		}
+	}
+}
+
+func TestParseCandidatesFieldScores(t *testing.T) {
+	candidates, err := ParseCandidates("12 Smith Stret Richmond VIC 3121")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) == 0 {
+		t.Fatal("expected a candidate")
+	}
+	candidate := candidates[0]
+	if candidate.Address.StreetType != "STREET" {
+		errorExpectedString(t, "STREET", candidate.Address.StreetType)
+	}
+	if score := candidate.FieldScores["StreetType"]; score <= 0 || score >= 1 {
+		t.Errorf("expected fuzzy StreetType score between 0 and 1, actual %f", score)
+	}
+	if score := candidate.FieldScores["State"]; score != 1 {
+		t.Errorf("expected exact State score of 1, actual %f", score)
+	}
+	if candidate.Confidence <= 0 || candidate.Confidence >= 1 {
+		t.Errorf("expected confidence between 0 and 1, actual %f", candidate.Confidence)
+	}
+
+	_, err = ParseCandidates("Smith")
+	if err == nil {
+		t.Error("expected error for short address")
+	}
+}
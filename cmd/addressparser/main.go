@@ -0,0 +1,271 @@
+// Command addressparser parses australian addresses, one per line, from
+// stdin or files and writes the parsed fields as json lines, csv or a table.
+//
+//	addressparser -format csv -reject rejects.txt addresses.txt
+//
+// With -csv the input is a csv file, the address columns are parsed and the
+// original columns are written with a column for each parsed field.
+//
+//	addressparser -csv -columns address1,address2,suburb customers.csv
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spid37/addressparser"
+)
+
+type options struct {
+	format  string
+	strict  bool
+	trace   bool
+	reject  string
+	csv     bool
+	columns string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	var opts options
+
+	flags := flag.NewFlagSet("addressparser", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.format, "format", "jsonl", "output format: jsonl, csv or table")
+	flags.BoolVar(&opts.strict, "strict", false, "fail addresses with unknown parts")
+	flags.BoolVar(&opts.trace, "trace", false, "write the steps of each parse to stderr")
+	flags.StringVar(&opts.reject, "reject", "", "write failed lines to this file instead of the output")
+	flags.BoolVar(&opts.csv, "csv", false, "input is a csv file, output the columns plus the parsed fields")
+	flags.StringVar(&opts.columns, "columns", "address", "comma separated csv columns to parse with -csv")
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: addressparser [flags] [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if opts.csv && flags.NArg() > 1 {
+		fmt.Fprintln(stderr, "only one csv file can be parsed at a time")
+		return 2
+	}
+	if opts.csv {
+		// csv output is always the columns plus the parsed fields
+		var unsupported []string
+		flags.Visit(func(f *flag.Flag) {
+			if f.Name == "format" || f.Name == "trace" {
+				unsupported = append(unsupported, "-"+f.Name)
+			}
+		})
+		if len(unsupported) > 0 {
+			fmt.Fprintf(stderr, "%s can't be used with -csv\n", strings.Join(unsupported, " and "))
+			return 2
+		}
+	}
+	output, err := newWriter(opts.format, stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	var rejects io.Writer
+	if opts.reject != "" {
+		rejectFile, err := os.Create(opts.reject)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer rejectFile.Close()
+		rejects = rejectFile
+	}
+
+	inputs := []io.Reader{stdin}
+	if flags.NArg() > 0 {
+		inputs = nil
+		for _, fileName := range flags.Args() {
+			file, err := os.Open(fileName)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+			defer file.Close()
+			inputs = append(inputs, file)
+		}
+	}
+
+	parser, err := addressparser.NewParser(
+		addressparser.WithStrict(opts.strict),
+		addressparser.WithTrace(opts.trace),
+	)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	var traces io.Writer
+	if opts.trace {
+		traces = stderr
+	}
+	if opts.csv {
+		err := parser.ParseCSV(inputs[0], stdout, addressparser.CSVOptions{
+			Columns: strings.Split(opts.columns, ","),
+			Rejects: rejects,
+		})
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, input := range inputs {
+		if err := parseLines(parser, input, output, rejects, traces); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	if err := output.Flush(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+func parseLines(
+	parser *addressparser.Parser, input io.Reader, output writer, rejects io.Writer, traces io.Writer,
+) error {
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		addressParts, err := parser.Parse(line)
+		if traces != nil && addressParts.Trace != nil {
+			if _, err := fmt.Fprintf(traces, "%s\n%s\n\n", line, addressParts.Trace); err != nil {
+				return err
+			}
+		}
+
+		if err != nil && rejects != nil {
+			if _, err := fmt.Fprintln(rejects, line); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := output.Write(line, addressParts, err); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+type writer interface {
+	Write(line string, addressParts *addressparser.AddressParts, parseErr error) error
+	Flush() error
+}
+
+func newWriter(format string, output io.Writer) (writer, error) {
+	switch format {
+	case "jsonl":
+		return &jsonWriter{encoder: json.NewEncoder(output)}, nil
+	case "csv":
+		return &csvWriter{writer: csv.NewWriter(output)}, nil
+	case "table":
+		return &tableWriter{writer: tabwriter.NewWriter(output, 0, 4, 2, ' ', 0)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+type jsonWriter struct {
+	encoder *json.Encoder
+}
+
+type jsonResult struct {
+	Input   string                      `json:"input"`
+	Address *addressparser.AddressParts `json:"address,omitempty"`
+	Error   string                      `json:"error,omitempty"`
+}
+
+func (jw *jsonWriter) Write(line string, addressParts *addressparser.AddressParts, parseErr error) error {
+	result := jsonResult{Input: line, Error: errorString(parseErr)}
+	if parseErr == nil {
+		result.Address = addressParts
+	}
+	return jw.encoder.Encode(result)
+}
+
+func (jw *jsonWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (cw *csvWriter) Write(line string, addressParts *addressparser.AddressParts, parseErr error) error {
+	if !cw.headerWritten {
+		header := append([]string{"Input"}, addressparser.FieldNames...)
+		if err := cw.writer.Write(append(header, "Error")); err != nil {
+			return err
+		}
+		cw.headerWritten = true
+	}
+	return cw.writer.Write(resultRow(line, addressParts, parseErr))
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+type tableWriter struct {
+	writer        *tabwriter.Writer
+	headerWritten bool
+}
+
+func (tw *tableWriter) Write(line string, addressParts *addressparser.AddressParts, parseErr error) error {
+	if !tw.headerWritten {
+		header := append([]string{"Input"}, addressparser.FieldNames...)
+		if _, err := fmt.Fprintln(tw.writer, strings.Join(append(header, "Error"), "\t")); err != nil {
+			return err
+		}
+		tw.headerWritten = true
+	}
+	_, err := fmt.Fprintln(tw.writer, strings.Join(resultRow(line, addressParts, parseErr), "\t"))
+	return err
+}
+
+func (tw *tableWriter) Flush() error {
+	return tw.writer.Flush()
+}
+
+// input line, parsed fields and the error
+func resultRow(line string, addressParts *addressparser.AddressParts, parseErr error) []string {
+	row := []string{line}
+	if parseErr == nil {
+		row = append(row, addressParts.FieldValues()...)
+	} else {
+		row = append(row, make([]string, len(addressparser.FieldNames))...)
+	}
+	return append(row, errorString(parseErr))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testInput = `1234 hello st Melbourne VIC 3000
+
+12 Smith Richmond VIC 3121
+`
+
+func TestRunJSONLines(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run(nil, strings.NewReader(testInput), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, actual %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, actual %d", len(lines))
+	}
+	var result jsonResult
+	if err := json.Unmarshal([]byte(lines[0]), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Address == nil || result.Address.StreetName != "HELLO" {
+		t.Errorf("unexpected result %s", lines[0])
+	}
+}
+
+func TestRunTrace(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-trace"}, strings.NewReader(testInput), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, actual %d: %s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stderr.String(), "1234 hello st Melbourne VIC 3000\n") ||
+		!strings.Contains(stderr.String(), "\npostal delivery: ") {
+		t.Errorf("unexpected trace %s", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "\n12 Smith Richmond VIC 3121\n") {
+		t.Errorf("expected a trace for each line, actual %s", stderr.String())
+	}
+}
+
+func TestRunStrictReject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addressparser")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	rejectFile := filepath.Join(dir, "rejects.txt")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-format", "csv", "-strict", "-reject", rejectFile}
+	code := run(args, strings.NewReader(testInput), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, actual %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "Input,FlatType") {
+		t.Errorf("unexpected csv output %q", stdout.String())
+	}
+	rejects, err := ioutil.ReadFile(rejectFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rejects) != "12 Smith Richmond VIC 3121\n" {
+		t.Errorf("unexpected rejects %q", string(rejects))
+	}
+}
+
+func TestRunUnknownFormat(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-format", "xml"}, strings.NewReader(testInput), &stdout, &stderr)
+	if code != 2 {
+		t.Errorf("expected exit code 2, actual %d", code)
+	}
+}
+
+func TestRunCSV(t *testing.T) {
+	input := "id,street,locality\n7,1234 hello st,Melbourne VIC 3000\n"
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-csv", "-columns", "street,locality"}
+	code := run(args, strings.NewReader(input), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit code 0, actual %d: %s", code, stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "id,street,locality,FlatType") {
+		t.Fatalf("unexpected csv output %q", stdout.String())
+	}
+	if !strings.Contains(lines[1], "HELLO,STREET,,MELBOURNE,VIC,3000") {
+		t.Errorf("unexpected csv row %q", lines[1])
+	}
+}
+
+func TestRunCSVUnsupportedFlags(t *testing.T) {
+	for _, args := range [][]string{{"-csv", "-format", "table"}, {"-csv", "-trace"}} {
+		var stdout, stderr bytes.Buffer
+		code := run(args, strings.NewReader("address\n1234 hello st\n"), &stdout, &stderr)
+		if code != 2 {
+			t.Errorf("%v: expected exit code 2, actual %d", args, code)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package addressparser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Dictionary - address type codes and names with indexes prebuilt for matching,
+// eg. ST: STREET. a dictionary is never changed once built, so it is safe to
+// share between parsers.
+//
+// if a string is the key of one entry and the value of another it matches the
+// key. a string in more than one dictionary, eg. PTHS is a flat and level type,
+// is matched by the first step of ProcessAddress that looks it up.
+type Dictionary struct {
+	entries map[string]string
+	// keys and values to fuzzy search
+	list []string
+	// key or value to key
+	keys map[string]string
+	// key or value with spaces removed to key
+	compactKeys map[string]string
+	// other names of a key, eg. AVE: AVENUE
+	aliases map[string]string
+}
+
+// NewDictionary create a dictionary from key value entries, keys and values are uppercased
+func NewDictionary(entries map[string]string) *Dictionary {
+	return newDictionary(entries, nil)
+}
+
+func newDictionary(entries map[string]string, aliases map[string]string) *Dictionary {
+	dict := &Dictionary{
+		entries:     make(map[string]string, len(entries)),
+		keys:        make(map[string]string, len(entries)*2),
+		compactKeys: make(map[string]string, len(entries)*2),
+		aliases:     make(map[string]string, len(aliases)),
+	}
+
+	sortedKeys := make([]string, 0, len(entries))
+	for key, value := range entries {
+		key = normaliseName(key)
+		dict.entries[key] = normaliseName(value)
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	for _, key := range sortedKeys {
+		dict.list = append(dict.list, key, dict.entries[key])
+	}
+	// values first so a key always maps to itself
+	for _, key := range sortedKeys {
+		value := dict.entries[key]
+		dict.keys[value] = key
+		dict.compactKeys[strings.Replace(value, " ", "", -1)] = key
+	}
+	for _, key := range sortedKeys {
+		dict.keys[key] = key
+		dict.compactKeys[strings.Replace(key, " ", "", -1)] = key
+	}
+
+	sortedAliases := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		sortedAliases = append(sortedAliases, alias)
+	}
+	sort.Strings(sortedAliases)
+	for _, alias := range sortedAliases {
+		key := aliases[alias]
+		// an alias of a removed key is dropped, a key or value is never an alias
+		if _, ok := dict.entries[key]; !ok || dict.Has(alias) {
+			continue
+		}
+		dict.aliases[alias] = key
+		dict.list = append(dict.list, alias)
+		dict.keys[alias] = key
+		dict.compactKeys[strings.Replace(alias, " ", "", -1)] = key
+	}
+
+	return dict
+}
+
+// Key get the key of a key, value or alias, empty if not found
+func (dict *Dictionary) Key(item string) string {
+	return dict.keys[item]
+}
+
+// Value get the value of a key, empty if not found
+func (dict *Dictionary) Value(key string) string {
+	return dict.entries[key]
+}
+
+// Has check if an item is a key, value or alias
+func (dict *Dictionary) Has(item string) bool {
+	_, ok := dict.keys[item]
+	return ok
+}
+
+// HasKey check if an item is a key
+func (dict *Dictionary) HasKey(item string) bool {
+	_, ok := dict.entries[item]
+	return ok
+}
+
+// check if words are the last words of a longer key or value, eg. GROUND FLOOR
+func (dict *Dictionary) hasEnding(words string) bool {
+	for _, item := range dict.list {
+		if strings.HasSuffix(item, " "+words) {
+			return true
+		}
+	}
+	return false
+}
+
+// Entries get a copy of the dictionary entries
+func (dict *Dictionary) Entries() map[string]string {
+	entries := make(map[string]string, len(dict.entries))
+	for key, value := range dict.entries {
+		entries[key] = value
+	}
+	return entries
+}
+
+// Extend create a new dictionary with entries added, an existing key is
+// overridden and a key with an empty value is removed
+func (dict *Dictionary) Extend(entries map[string]string) *Dictionary {
+	extended := dict.Entries()
+	for key, value := range entries {
+		key = normaliseName(key)
+		if value == "" {
+			delete(extended, key)
+			continue
+		}
+		extended[key] = value
+	}
+	return newDictionary(extended, dict.aliases)
+}
+
+// Alias create a new dictionary with other names for keys, eg. AVE: AVENUE.
+// an alias matches like a value but isn't used for formatting
+func (dict *Dictionary) Alias(aliases map[string]string) *Dictionary {
+	merged := make(map[string]string, len(dict.aliases)+len(aliases))
+	for alias, key := range dict.aliases {
+		merged[alias] = key
+	}
+	for alias, key := range aliases {
+		merged[normaliseName(alias)] = normaliseName(key)
+	}
+	return newDictionary(dict.entries, merged)
+}
+
+// Dictionaries - the dictionaries used to parse an address
+type Dictionaries struct {
+	FlatTypes           *Dictionary
+	LevelTypes          *Dictionary
+	PostalDeliveryTypes *Dictionary
+	LotTypes            *Dictionary
+	PlanTypes           *Dictionary
+	// flat, level and postal types that have no number, eg. GROUND FLOOR
+	NoNumberTypes *Dictionary
+	States        *Dictionary
+	StreetTypes   *Dictionary
+	// street types that are numbered rather than named, eg. HIGHWAY 1
+	NumberedStreetTypes *Dictionary
+	StreetSuffixes      *Dictionary
+}
+
+var defaultDictionaries = &Dictionaries{
+	FlatTypes:           NewDictionary(flatTypes),
+	LevelTypes:          NewDictionary(levelTypes),
+	PostalDeliveryTypes: NewDictionary(postalDeliveryTypes),
+	LotTypes:            NewDictionary(lotTypes),
+	PlanTypes:           NewDictionary(planTypes),
+	NoNumberTypes:       NewDictionary(addressTypesNoNumber),
+	States:              NewDictionary(australianStates),
+	StreetTypes:         NewDictionary(streetTypes).Alias(streetTypeAliases),
+	NumberedStreetTypes: NewDictionary(numberedStreetTypes),
+	StreetSuffixes:      NewDictionary(streetSuffixes),
+}
+
+// DefaultDictionaries get a copy of the default dictionaries to extend
+func DefaultDictionaries() *Dictionaries {
+	dictionaries := *defaultDictionaries
+	return &dictionaries
+}
+
+// names of the dictionaries, see LoadJSON
+var dictionaryNames = []string{
+	"flat_types", "level_types", "postal_delivery_types", "lot_types", "plan_types",
+	"no_number_types", "states", "street_types", "numbered_street_types", "street_suffixes",
+}
+
+func (d *Dictionaries) byName(name string) **Dictionary {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "flat_types":
+		return &d.FlatTypes
+	case "level_types":
+		return &d.LevelTypes
+	case "postal_delivery_types":
+		return &d.PostalDeliveryTypes
+	case "lot_types":
+		return &d.LotTypes
+	case "plan_types":
+		return &d.PlanTypes
+	case "no_number_types":
+		return &d.NoNumberTypes
+	case "states":
+		return &d.States
+	case "street_types":
+		return &d.StreetTypes
+	case "numbered_street_types":
+		return &d.NumberedStreetTypes
+	case "street_suffixes":
+		return &d.StreetSuffixes
+	}
+	return nil
+}
+
+// Extend extend a dictionary by name, the names are listed in LoadJSON
+func (d *Dictionaries) Extend(name string, entries map[string]string) error {
+	dict := d.byName(name)
+	if dict == nil {
+		return errors.Errorf("Unknown dictionary %s", name)
+	}
+	if *dict == nil {
+		// a dictionary left out is the default
+		*dict = *defaultDictionaries.byName(name)
+	}
+	*dict = (*dict).Extend(entries)
+	return nil
+}
+
+func (d *Dictionaries) extendAll(named map[string]map[string]string) error {
+	for name, entries := range named {
+		if err := d.Extend(name, entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadJSON extend the dictionaries from json of dictionary name to entries,
+// the names are flat_types, level_types, postal_delivery_types, lot_types,
+// plan_types, no_number_types, states, street_types, numbered_street_types and
+// street_suffixes.
+//
+//	{"street_types": {"RIDGEWAY": "RGWY", "GREENWAY": "GNWY"}}
+func (d *Dictionaries) LoadJSON(reader io.Reader) error {
+	var named map[string]map[string]string
+	if err := json.NewDecoder(reader).Decode(&named); err != nil {
+		return errors.Wrap(err, "Failed to read dictionary json")
+	}
+	return d.extendAll(named)
+}
+
+// LoadYAML extend the dictionaries from yaml in the same format as LoadJSON
+func (d *Dictionaries) LoadYAML(reader io.Reader) error {
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return errors.Wrap(err, "Failed to read dictionary yaml")
+	}
+	var named map[string]map[string]string
+	if err := yaml.Unmarshal(data, &named); err != nil {
+		return errors.Wrap(err, "Failed to read dictionary yaml")
+	}
+	return d.extendAll(named)
+}
+
+// LoadCSV extend the dictionaries from csv rows of dictionary name, key, value
+//
+//	street_types,RIDGEWAY,RGWY
+func (d *Dictionaries) LoadCSV(reader io.Reader) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+
+	named := make(map[string]map[string]string)
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return errors.Wrap(err, "Failed to read dictionary csv")
+		}
+		name := strings.ToLower(strings.TrimSpace(record[0]))
+		if line == 1 && d.byName(name) == nil {
+			// skip the header row
+			continue
+		}
+		if named[name] == nil {
+			named[name] = make(map[string]string)
+		}
+		named[name][record[1]] = record[2]
+	}
+	return d.extendAll(named)
+}
+
+// LoadFile extend the dictionaries from a json, yaml or csv file
+func (d *Dictionaries) LoadFile(fileName string) error {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return errors.Wrap(err, "Failed to open dictionary file")
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".json":
+		return d.LoadJSON(file)
+	case ".yaml", ".yml":
+		return d.LoadYAML(file)
+	case ".csv":
+		return d.LoadCSV(file)
+	}
+	return errors.Errorf("Unknown dictionary file type %s", fileName)
+}
@@ -0,0 +1,195 @@
+package addressparser
+
+import (
+	"strings"
+	"testing"
+)
+
+// number of times to repeat a parse to check the result never changes
+const determinismRuns = 50
+
+func TestFuzzyMatchTieBreak(t *testing.T) {
+	dict := NewDictionary(map[string]string{
+		"ZZA": "CAT",
+		"ZZB": "CAR",
+		"CD":  "ZZC",
+		"ZZD": "CD",
+	})
+
+	for i := 0; i < determinismRuns; i++ {
+		// CAT and CAR are the same distance, the first alphabetically wins
+		result, distance := fuzzyMatch("CA", dict, FuzzySearch{}, FuzzySearchThreshold)
+		if len(result) != 1 || result[0] != "CAR" || distance != 1 {
+			t.Fatalf("expected CAR distance 1, actual %v distance %.1f", result, distance)
+		}
+		// CD is a key and a value, the key wins
+		if dict.Key("CD") != "CD" {
+			errorExpectedString(t, "CD", dict.Key("CD"))
+		}
+	}
+}
+
+func TestDeterministicParse(t *testing.T) {
+	addresses := []string{
+		"Penthouse 2 12 Smith St Richmond VIC 3121",
+		"PTHS 4 88 King St Sydney NSW 2000",
+		"SE 1 12 Smith St SE Richmond VIC 3121",
+		"12 Smith Stret Richmond VIC 3121",
+	}
+	for _, testAddress := range testAddresses {
+		addresses = append(addresses, testAddress.AddressString)
+	}
+
+	for _, address := range addresses {
+		first, err := NewAddress(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		firstCandidates, err := ParseCandidates(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 0; i < determinismRuns; i++ {
+			addressParts, _ := NewAddress(address)
+			if !sameAddress(first, addressParts) {
+				t.Fatalf("%s: parse changed between runs", address)
+			}
+			candidates, _ := ParseCandidates(address)
+			if len(candidates) != len(firstCandidates) {
+				t.Fatalf("%s: candidates changed between runs", address)
+			}
+			for j, candidate := range candidates {
+				if candidate.Confidence != firstCandidates[j].Confidence ||
+					!sameAddress(candidate.Address, firstCandidates[j].Address) {
+					t.Fatalf("%s: candidates changed between runs", address)
+				}
+			}
+		}
+	}
+}
+
+func TestDictionariesLoad(t *testing.T) {
+	loadTests := []struct {
+		name string
+		load func(*Dictionaries) error
+	}{
+		{"json", func(d *Dictionaries) error {
+			return d.LoadJSON(strings.NewReader(`{"street_types": {"RIDGEWAY": "RGWY"}, "flat_types": {"POD": "POD"}}`))
+		}},
+		{"yaml", func(d *Dictionaries) error {
+			return d.LoadYAML(strings.NewReader("street_types:\n  RIDGEWAY: RGWY\nflat_types:\n  POD: POD\n"))
+		}},
+		{"csv", func(d *Dictionaries) error {
+			return d.LoadCSV(strings.NewReader("dictionary,key,value\nstreet_types,ridgeway,rgwy\nflat_types,POD,POD\n"))
+		}},
+	}
+
+	address := "Pod 4 12 Smith Ridgeway Richmond VIC 3121"
+	for _, loadTest := range loadTests {
+		dictionaries := DefaultDictionaries()
+		if err := loadTest.load(dictionaries); err != nil {
+			t.Fatalf("%s: %s", loadTest.name, err)
+		}
+		addressParts, err := newTestParser(t, WithDictionaries(dictionaries)).Parse(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addressParts.StreetType != "RIDGEWAY" || addressParts.FlatType != "POD" {
+			t.Errorf("%s: expected RIDGEWAY and POD, actual %s and %s",
+				loadTest.name, addressParts.StreetType, addressParts.FlatType)
+		}
+		if addressParts.String() != "POD 4, 12 SMITH RGWY, RICHMOND VIC 3121" {
+			errorExpectedString(t, "POD 4, 12 SMITH RGWY, RICHMOND VIC 3121", addressParts.String())
+		}
+	}
+
+	// the defaults are not changed
+	addressParts, err := NewAddress(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.StreetType == "RIDGEWAY" {
+		t.Error("default dictionaries changed")
+	}
+
+	err = DefaultDictionaries().LoadJSON(strings.NewReader(`{"road_types": {"X": "Y"}}`))
+	if err == nil {
+		t.Error("expected unknown dictionary error")
+	}
+
+	if _, err := NewParser(WithDictionaries(nil)); err == nil {
+		t.Error("expected missing dictionaries error")
+	}
+
+	// extended numbered street types, eg. BYPASS 3
+	dictionaries := DefaultDictionaries()
+	if err := dictionaries.Extend("numbered_street_types", map[string]string{"BYPASS": "BYPA"}); err != nil {
+		t.Fatal(err)
+	}
+	addressParts, err = newTestParser(t, WithDictionaries(dictionaries)).Parse("12 Bypass 3 Fitzroy VIC 3065")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.StreetName != "BYPASS 3" || addressParts.Suburb != "FITZROY" {
+		t.Errorf("expected BYPASS 3 in FITZROY, actual %s", addressParts)
+	}
+
+	// loading into zero value dictionaries extends the defaults
+	dictionaries = new(Dictionaries)
+	if err := dictionaries.LoadYAML(strings.NewReader("street_types:\n  RIDGEWAY: RGWY\n")); err != nil {
+		t.Fatal(err)
+	}
+	if !dictionaries.StreetTypes.HasKey("RIDGEWAY") || !dictionaries.StreetTypes.HasKey("STREET") {
+		t.Error("expected the default street types extended with RIDGEWAY")
+	}
+
+	// dictionaries left out of a literal are the defaults
+	parser := newTestParser(t, WithDictionaries(&Dictionaries{
+		StreetTypes: DefaultDictionaries().StreetTypes,
+		LotTypes:    NewDictionary(map[string]string{"ALLOTMENT": "LOT"}),
+	}))
+	addressParts, err = parser.Parse("Allotment 5 DP 123456 Smith Rd Wagga Wagga NSW 2650")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.LotNumber != 5 || addressParts.PlanType != "DP" || addressParts.State != "NSW" {
+		t.Errorf("expected lot 5 DP NSW, actual %s", addressParts)
+	}
+}
+
+func TestDictionaryExtend(t *testing.T) {
+	dict := NewDictionary(map[string]string{"ST": "STREET", "RD": "ROAD"})
+	extended := dict.Extend(map[string]string{"rd": "", "av": "avenue"})
+
+	if !dict.HasKey("RD") || dict.HasKey("AV") {
+		t.Error("extend changed the original dictionary")
+	}
+	if extended.HasKey("RD") {
+		t.Error("expected RD to be removed")
+	}
+	if extended.Key("AVENUE") != "AV" {
+		errorExpectedString(t, "AV", extended.Key("AVENUE"))
+	}
+
+}
+
+func TestDictionaryAlias(t *testing.T) {
+	dict := NewDictionary(map[string]string{"AVENUE": "AV", "ROAD": "RD"}).Alias(map[string]string{"ave": "avenue"})
+
+	if dict.Key("AVE") != "AVENUE" {
+		errorExpectedString(t, "AVENUE", dict.Key("AVE"))
+	}
+	if !dict.Has("AVE") || dict.HasKey("AVE") {
+		t.Error("expected AVE to be an alias")
+	}
+	if dict.Value("AVENUE") != "AV" {
+		errorExpectedString(t, "AV", dict.Value("AVENUE"))
+	}
+	if dict.Extend(map[string]string{"ROAD": ""}).Key("AVE") != "AVENUE" {
+		t.Error("expected extend to keep the aliases")
+	}
+	if dict.Extend(map[string]string{"AVENUE": ""}).Has("AVE") {
+		t.Error("expected the alias of a removed key to be removed")
+	}
+}
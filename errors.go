@@ -0,0 +1,89 @@
+package addressparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseErrorKind - the reason an address failed to parse
+type ParseErrorKind int
+
+// kinds of parse error
+const (
+	ErrAddressTooShort ParseErrorKind = iota + 1
+	ErrLeftoverParts
+	ErrNoStreetName
+	ErrUnknownStreetType
+)
+
+var parseErrorMessages = map[ParseErrorKind]string{
+	ErrAddressTooShort:   "Address string too short",
+	ErrLeftoverParts:     "Address has unknown parts",
+	ErrNoStreetName:      "Address has no street name",
+	ErrUnknownStreetType: "Address has an unknown street type",
+}
+
+// ParseError - error with the kind and the address parts that caused it
+type ParseError struct {
+	Kind ParseErrorKind
+	// index of the parts in AddressStringParts
+	Positions []int
+	Parts     []string
+	// locality in the address lines that disagrees with the fields, see ParseLines
+	Conflicts []string
+}
+
+func (pe *ParseError) Error() string {
+	message := parseErrorMessages[pe.Kind]
+	parts := append(append([]string{}, pe.Parts...), pe.Conflicts...)
+	if len(parts) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s: %s", message, strings.Join(parts, " "))
+}
+
+// IsParseError check if an error, or the cause of a wrapped error, is a parse error of the kind
+func IsParseError(err error, kind ParseErrorKind) bool {
+	parseError, ok := errors.Cause(err).(*ParseError)
+	return ok && parseError.Kind == kind
+}
+
+// NewAddressStrict parse an address string, failing if any part of
+// the address was not recognised
+func NewAddressStrict(address string) (*AddressParts, error) {
+	addressParts, err := NewAddress(address)
+	if err != nil {
+		return addressParts, err
+	}
+	return addressParts, addressParts.Check()
+}
+
+// Check check a processed address was fully parsed
+func (ap *AddressParts) Check() error {
+	var positions []int
+	var parts []string
+	for index, addressPart := range ap.AddressStringParts {
+		if addressPart != "" {
+			positions = append(positions, index)
+			parts = append(parts, addressPart)
+		}
+	}
+
+	// postal addresses don't need a street
+	if ap.PostalDeliveryType == "" {
+		// a street number should be followed by a street name and type
+		if ap.StreetType == "" && !ap.NoStreetType && (ap.StreetNumber != 0 || ap.StreetName != "") {
+			return &ParseError{Kind: ErrUnknownStreetType, Positions: positions, Parts: parts, Conflicts: ap.conflicts}
+		}
+		if ap.StreetName == "" {
+			return &ParseError{Kind: ErrNoStreetName, Positions: positions, Parts: parts, Conflicts: ap.conflicts}
+		}
+	}
+	if len(parts) > 0 || len(ap.conflicts) > 0 {
+		return &ParseError{Kind: ErrLeftoverParts, Positions: positions, Parts: parts, Conflicts: ap.conflicts}
+	}
+
+	return nil
+}
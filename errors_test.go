@@ -0,0 +1,53 @@
+package addressparser
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewAddressStrict(t *testing.T) {
+	strictTests := []struct {
+		address string
+		kind    ParseErrorKind
+		parts   []string
+	}{
+		{"1234 hello st Melbourne VIC 3000", 0, nil},
+		{"PO Box 123 Fitzroy VIC 3065", 0, nil},
+		{"Hello 3000", ErrAddressTooShort, []string{"HELLO", "3000"}},
+		{"12 Smith Richmond VIC 3121", ErrUnknownStreetType, []string{"SMITH", "RICHMOND"}},
+		{"Melbourne VIC 3000", ErrNoStreetName, []string{"MELBOURNE"}},
+	}
+
+	for _, strictTest := range strictTests {
+		_, err := NewAddressStrict(strictTest.address)
+		if strictTest.kind == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %s", strictTest.address, err)
+			}
+			continue
+		}
+		if !IsParseError(err, strictTest.kind) {
+			t.Errorf("%s: expected parse error kind %d, actual %v", strictTest.address, strictTest.kind, err)
+			continue
+		}
+		parseError := err.(*ParseError)
+		if len(parseError.Parts) != len(strictTest.parts) {
+			t.Errorf("%s: expected parts %v, actual %v", strictTest.address, strictTest.parts, parseError.Parts)
+			continue
+		}
+		for i, part := range parseError.Parts {
+			if part != strictTest.parts[i] {
+				errorExpectedString(t, strictTest.parts[i], part)
+			}
+		}
+	}
+}
+
+func TestIsParseErrorWrapped(t *testing.T) {
+	_, err := NewAddressStrict("Hello 3000")
+	err = errors.Wrap(err, "Failed to parse address")
+	if !IsParseError(err, ErrAddressTooShort) {
+		t.Errorf("expected wrapped parse error, actual %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package addressparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatStyle - how to format an address as a string
+type FormatStyle int
+
+// address format styles
+const (
+	// FormatAbbreviated single line using abbreviated types, eg. 12 SMITH ST
+	FormatAbbreviated FormatStyle = iota
+	// FormatExpanded single line using full type names, eg. 12 SMITH STREET
+	FormatExpanded
+	// FormatLabel australia post label lines, eg. MELBOURNE  VIC  3000
+	FormatLabel
+)
+
+func (ap *AddressParts) String() string {
+	return ap.Format(FormatAbbreviated)
+}
+
+// Format format the address in a style, label lines are separated by a newline
+func (ap *AddressParts) Format(style FormatStyle) string {
+	if style == FormatLabel {
+		return strings.Join(ap.LabelLines(), "\n")
+	}
+
+	var lines []string
+	expanded := style == FormatExpanded
+	if ap.BuildingName != "" {
+		lines = append(lines, ap.BuildingName)
+	}
+	if subDwelling := ap.formatSubDwelling(expanded); subDwelling != "" {
+		lines = append(lines, subDwelling)
+	}
+	if street := ap.formatStreet(expanded); street != "" {
+		lines = append(lines, street)
+	}
+
+	locality := ap.Suburb
+	if expanded && ap.dicts().States.Value(ap.State) != "" {
+		locality = joinNonEmpty(" ", locality, ap.dicts().States.Value(ap.State), ap.formatPostCode())
+	} else {
+		locality = joinNonEmpty(" ", locality, ap.State, ap.formatPostCode())
+	}
+	if locality != "" {
+		lines = append(lines, locality)
+	}
+
+	return strings.Join(lines, ", ")
+}
+
+// LabelLines format the address as australia post label lines
+func (ap *AddressParts) LabelLines() []string {
+	var lines []string
+
+	if ap.BuildingName != "" {
+		lines = append(lines, ap.BuildingName)
+	}
+	if subDwelling := ap.formatSubDwelling(true); subDwelling != "" {
+		lines = append(lines, subDwelling)
+	}
+	if street := ap.formatStreet(false); street != "" {
+		lines = append(lines, street)
+	}
+	// state and postcode are separated by two spaces on the locality line
+	locality := joinNonEmpty("  ", ap.Suburb, ap.State, ap.formatPostCode())
+	if locality != "" {
+		lines = append(lines, strings.ToUpper(locality))
+	}
+
+	return lines
+}
+
+// flat and level, eg. UNIT 3 LEVEL 2
+func (ap *AddressParts) formatSubDwelling(expanded bool) string {
+	var flat, level string
+
+	if ap.FlatType != "" || (ap.FlatNumber != 0 && !ap.isSlashFlat()) {
+		flatType := ap.FlatType
+		if expanded && ap.dicts().FlatTypes.Value(flatType) != "" {
+			flatType = ap.dicts().FlatTypes.Value(flatType)
+		}
+		flat = joinNonEmpty(" ", flatType, formatNumber(ap.FlatNumber, ap.FlatNumberSuffix, 0))
+	}
+	if ap.LevelType != "" {
+		levelType := ap.LevelType
+		if expanded && ap.dicts().LevelTypes.Value(levelType) != "" {
+			levelType = ap.dicts().LevelTypes.Value(levelType)
+		}
+		level = joinNonEmpty(" ", levelType, formatNumber(ap.LevelNumber, "", 0))
+	}
+
+	return joinNonEmpty(" ", flat, level)
+}
+
+// a flat number without a flat type is written before the street number, eg. 3A/12-14
+func (ap *AddressParts) isSlashFlat() bool {
+	return ap.FlatType == "" && ap.FlatNumber != 0 && ap.StreetNumber != 0
+}
+
+// street or postal delivery line, eg. 12 SMITH ST, CNR SMITH ST & JONES RD or PO BOX 123
+func (ap *AddressParts) formatStreet(expanded bool) string {
+	if ap.PostalDeliveryType != "" {
+		postalType := ap.PostalDeliveryType
+		if expanded && ap.dicts().PostalDeliveryTypes.Value(postalType) != "" {
+			postalType = ap.dicts().PostalDeliveryTypes.Value(postalType)
+		}
+		postalNumber := formatNumber(ap.PostalDeliveryNumber, ap.PostalDeliveryNumberSuffix, 0)
+		if postalNumber != "" {
+			postalNumber = ap.PostalDeliveryNumberPrefix + postalNumber
+		}
+		return joinNonEmpty(" ", postalType, postalNumber)
+	}
+
+	streetNumber := formatNumber(ap.StreetNumber, ap.StreetNumberSuffix, ap.StreetNumberEnd)
+	if ap.isSlashFlat() {
+		streetNumber = formatNumber(ap.FlatNumber, ap.FlatNumberSuffix, 0) + "/" + streetNumber
+	}
+	street := joinNonEmpty(
+		" ",
+		ap.formatLot(expanded),
+		streetNumber,
+		ap.formatStreetName(ap.StreetName, ap.StreetType, ap.StreetSuffix, expanded),
+	)
+	if ap.CornerStreetName == "" {
+		return street
+	}
+
+	corner := "CNR"
+	if expanded {
+		corner = "CORNER"
+	}
+	return joinNonEmpty(
+		" ",
+		corner,
+		street,
+		"&",
+		ap.formatStreetName(ap.CornerStreetName, ap.CornerStreetType, ap.CornerStreetSuffix, expanded),
+	)
+}
+
+// lot and plan, eg. LOT 5 DP 123456
+func (ap *AddressParts) formatLot(expanded bool) string {
+	var lot string
+	if ap.LotNumber != 0 {
+		lot = "LOT " + formatNumber(ap.LotNumber, ap.LotNumberSuffix, 0)
+	}
+	planType := ap.PlanType
+	if expanded && ap.dicts().PlanTypes.Value(planType) != "" {
+		planType = ap.dicts().PlanTypes.Value(planType)
+	}
+	return joinNonEmpty(" ", lot, planType, ap.PlanNumber)
+}
+
+// street name with its type and suffix, eg. SMITH ST N
+func (ap *AddressParts) formatStreetName(name, streetType, streetSuffix string, expanded bool) string {
+	if !expanded && ap.dicts().StreetTypes.Value(streetType) != "" {
+		streetType = ap.dicts().StreetTypes.Value(streetType)
+	}
+	if expanded && ap.dicts().StreetSuffixes.Value(streetSuffix) != "" {
+		streetSuffix = ap.dicts().StreetSuffixes.Value(streetSuffix)
+	}
+	return joinNonEmpty(" ", name, streetType, streetSuffix)
+}
+
+func (ap *AddressParts) formatPostCode() string {
+	if ap.PostCode == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%04d", ap.PostCode)
+}
+
+// format a number with its suffix or range end, eg. 12A or 12-14
+func formatNumber(number int, suffix string, end int) string {
+	if number == 0 {
+		return ""
+	}
+	formatted := strconv.Itoa(number) + suffix
+	if end != 0 {
+		formatted = fmt.Sprintf("%s-%d", formatted, end)
+	}
+	return formatted
+}
+
+func joinNonEmpty(separator string, items ...string) string {
+	var output []string
+	for _, item := range items {
+		if item != "" {
+			output = append(output, item)
+		}
+	}
+	return strings.Join(output, separator)
+}
+
+// FieldNames - names of the parsed address fields, in the order of FieldValues
+var FieldNames = []string{
+	"FlatType",
+	"FlatNumber",
+	"FlatNumberSuffix",
+	"LevelType",
+	"LevelNumber",
+	"StreetNumber",
+	"StreetNumberEnd",
+	"StreetNumberSuffix",
+	"StreetName",
+	"StreetType",
+	"StreetSuffix",
+	"Suburb",
+	"State",
+	"PostCode",
+	"PostalDeliveryType",
+	"PostalDeliveryNumber",
+	"PostalDeliveryNumberPrefix",
+	"PostalDeliveryNumberSuffix",
+	"CornerStreetName",
+	"CornerStreetType",
+	"CornerStreetSuffix",
+	"BuildingName",
+	"LotNumber",
+	"LotNumberSuffix",
+	"PlanType",
+	"PlanNumber",
+}
+
+// FieldValues get the parsed address fields as strings, empty if not found
+func (ap *AddressParts) FieldValues() []string {
+	return []string{
+		ap.FlatType,
+		formatNumber(ap.FlatNumber, "", 0),
+		ap.FlatNumberSuffix,
+		ap.LevelType,
+		formatNumber(ap.LevelNumber, "", 0),
+		formatNumber(ap.StreetNumber, "", 0),
+		formatNumber(ap.StreetNumberEnd, "", 0),
+		ap.StreetNumberSuffix,
+		ap.StreetName,
+		ap.StreetType,
+		ap.StreetSuffix,
+		ap.Suburb,
+		ap.State,
+		ap.formatPostCode(),
+		ap.PostalDeliveryType,
+		formatNumber(ap.PostalDeliveryNumber, "", 0),
+		ap.PostalDeliveryNumberPrefix,
+		ap.PostalDeliveryNumberSuffix,
+		ap.CornerStreetName,
+		ap.CornerStreetType,
+		ap.CornerStreetSuffix,
+		ap.BuildingName,
+		formatNumber(ap.LotNumber, "", 0),
+		ap.LotNumberSuffix,
+		ap.PlanType,
+		ap.PlanNumber,
+	}
+}
@@ -0,0 +1,100 @@
+package addressparser
+
+import (
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	formatTests := []struct {
+		address     string
+		abbreviated string
+		expanded    string
+		label       string
+	}{
+		{
+			"1234 hello st Melbourne VIC 3000",
+			"1234 HELLO ST, MELBOURNE VIC 3000",
+			"1234 HELLO STREET, MELBOURNE VICTORIA 3000",
+			"1234 HELLO ST\nMELBOURNE  VIC  3000",
+		},
+		{
+			"Suite 1 Level 14, 200 Queen St, Melbourne VIC 3000",
+			"SE 1 L 14, 200 QUEEN ST, MELBOURNE VIC 3000",
+			"SUITE 1 LEVEL 14, 200 QUEEN STREET, MELBOURNE VICTORIA 3000",
+			"SUITE 1 LEVEL 14\n200 QUEEN ST\nMELBOURNE  VIC  3000",
+		},
+		{
+			"3A/12-14 Park Av N, Eildon VIC 3713",
+			"3A/12-14 PARK AV N, EILDON VIC 3713",
+			"3A/12-14 PARK AVENUE NORTH, EILDON VICTORIA 3713",
+			"3A/12-14 PARK AV N\nEILDON  VIC  3713",
+		},
+		{
+			"123 The Boulevarde, Flat Oak NSW 2529",
+			"123 THE BOULEVARDE, FLAT OAK NSW 2529",
+			"123 THE BOULEVARDE, FLAT OAK NEW SOUTH WALES 2529",
+			"123 THE BOULEVARDE\nFLAT OAK  NSW  2529",
+		},
+		{
+			"Cnr Smith St & Jones Rd, Fitzroy VIC 3065",
+			"CNR SMITH ST & JONES RD, FITZROY VIC 3065",
+			"CORNER SMITH STREET & JONES ROAD, FITZROY VICTORIA 3065",
+			"CNR SMITH ST & JONES RD\nFITZROY  VIC  3065",
+		},
+		{
+			"Level 2 Rialto Towers 525 Collins St Melbourne VIC 3000",
+			"RIALTO TOWERS, L 2, 525 COLLINS ST, MELBOURNE VIC 3000",
+			"RIALTO TOWERS, LEVEL 2, 525 COLLINS STREET, MELBOURNE VICTORIA 3000",
+			"RIALTO TOWERS\nLEVEL 2\n525 COLLINS ST\nMELBOURNE  VIC  3000",
+		},
+		{
+			"Lot 5 DP 123456 Smith Rd Wagga Wagga NSW 2650",
+			"LOT 5 DP 123456 SMITH RD, WAGGA WAGGA NSW 2650",
+			"LOT 5 DEPOSITED PLAN 123456 SMITH ROAD, WAGGA WAGGA NEW SOUTH WALES 2650",
+			"LOT 5 DP 123456 SMITH RD\nWAGGA WAGGA  NSW  2650",
+		},
+		{
+			"45 Highway 1 Glenrowan VIC 3675",
+			"45 HIGHWAY 1, GLENROWAN VIC 3675",
+			"45 HIGHWAY 1, GLENROWAN VICTORIA 3675",
+			"45 HIGHWAY 1\nGLENROWAN  VIC  3675",
+		},
+		{
+			"GPO Box A12 Darwin NT 0801",
+			"GPO BOX A12, DARWIN NT 0801",
+			"GENERAL POST OFFICE BOX A12, DARWIN NORTHERN TERRITORY 0801",
+			"GPO BOX A12\nDARWIN  NT  0801",
+		},
+	}
+
+	for _, formatTest := range formatTests {
+		addressParts, err := NewAddress(formatTest.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addressParts.String() != formatTest.abbreviated {
+			errorExpectedString(t, formatTest.abbreviated, addressParts.String())
+		}
+		if addressParts.Format(FormatExpanded) != formatTest.expanded {
+			errorExpectedString(t, formatTest.expanded, addressParts.Format(FormatExpanded))
+		}
+		if addressParts.Format(FormatLabel) != formatTest.label {
+			errorExpectedString(t, formatTest.label, addressParts.Format(FormatLabel))
+		}
+	}
+}
+
+func TestFormatUnknownTypes(t *testing.T) {
+	// a type not in the dictionaries is kept as it is
+	addressParts := &AddressParts{
+		PostalDeliveryType:   "PARCEL LOCKER",
+		PostalDeliveryNumber: 12,
+		Suburb:               "FITZROY",
+		State:                "VIC",
+		PostCode:             3065,
+	}
+	expected := "PARCEL LOCKER 12, FITZROY VICTORIA 3065"
+	if addressParts.Format(FormatExpanded) != expected {
+		errorExpectedString(t, expected, addressParts.Format(FormatExpanded))
+	}
+}
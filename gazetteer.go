@@ -0,0 +1,255 @@
+package addressparser
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Locality - a suburb with its state and postcode
+type Locality struct {
+	Suburb   string
+	State    string
+	PostCode int
+}
+
+// Gazetteer lookup localities by suburb or postcode
+type Gazetteer interface {
+	LocalitiesBySuburb(suburb string) []Locality
+	LocalitiesByPostCode(postCode int) []Locality
+}
+
+// LocalityList - in memory gazetteer
+type LocalityList struct {
+	bySuburb   map[string][]Locality
+	byPostCode map[int][]Locality
+}
+
+// NewLocalityList create a gazetteer from a list of localities
+func NewLocalityList(localities []Locality) *LocalityList {
+	localityList := &LocalityList{
+		bySuburb:   make(map[string][]Locality),
+		byPostCode: make(map[int][]Locality),
+	}
+	for _, locality := range localities {
+		localityList.Add(locality)
+	}
+	return localityList
+}
+
+// LoadLocalityCSV load a gazetteer from csv rows of suburb, state, postcode
+func LoadLocalityCSV(reader io.Reader) (*LocalityList, error) {
+	localityList := NewLocalityList(nil)
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+
+	for line := 1; ; line++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return localityList, errors.Wrap(err, "Failed to read locality csv")
+		}
+		postCode, err := strconv.Atoi(strings.TrimSpace(record[2]))
+		if err != nil {
+			if line == 1 {
+				// skip the header row
+				continue
+			}
+			return localityList, errors.Wrapf(err, "Invalid postcode on line %d", line)
+		}
+		localityList.Add(Locality{
+			Suburb:   record[0],
+			State:    record[1],
+			PostCode: postCode,
+		})
+	}
+
+	return localityList, nil
+}
+
+// LoadLocalityFile load a gazetteer from a csv file
+func LoadLocalityFile(fileName string) (*LocalityList, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open locality file")
+	}
+	defer file.Close()
+
+	return LoadLocalityCSV(file)
+}
+
+// Add add a locality to the gazetteer
+func (ll *LocalityList) Add(locality Locality) {
+	locality.Suburb = normaliseName(locality.Suburb)
+	locality.State = normaliseName(locality.State)
+
+	key := NameKey(locality.Suburb)
+	ll.bySuburb[key] = append(ll.bySuburb[key], locality)
+	ll.byPostCode[locality.PostCode] = append(ll.byPostCode[locality.PostCode], locality)
+}
+
+// LocalitiesBySuburb get all localities with the suburb name
+func (ll *LocalityList) LocalitiesBySuburb(suburb string) []Locality {
+	return ll.bySuburb[NameKey(suburb)]
+}
+
+// LocalitiesByPostCode get all localities with the postcode
+func (ll *LocalityList) LocalitiesByPostCode(postCode int) []Locality {
+	return ll.byPostCode[postCode]
+}
+
+func normaliseName(name string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(name)), " ")
+}
+
+// replaces the apostrophes and hyphens of a name for NameKey
+var nameKeyReplacer = strings.NewReplacer("'", "", rightQuote, "", "-", " ")
+
+// NameKey the key to compare names with, without the apostrophes and hyphens
+// of the official spelling, eg. HALL'S CREEK is HALLS CREEK and BEL-AIR is BEL AIR
+func NameKey(name string) string {
+	return normaliseName(nameKeyReplacer.Replace(name))
+}
+
+// ValidationError - a parsed field that does not match the gazetteer
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (ve ValidationError) Error() string {
+	return ve.Message
+}
+
+// Validate check the suburb, state and postcode are consistent with the gazetteer
+func (ap *AddressParts) Validate(gazetteer Gazetteer) []ValidationError {
+	var validationErrors []ValidationError
+
+	if ap.PostCode != 0 {
+		postCodeLocalities := gazetteer.LocalitiesByPostCode(ap.PostCode)
+		if len(postCodeLocalities) == 0 {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "PostCode",
+				Message: fmt.Sprintf("%04d is not a known postcode", ap.PostCode),
+			})
+		} else if ap.State != "" && !localitiesHaveState(postCodeLocalities, ap.State) {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "PostCode",
+				Message: fmt.Sprintf("%04d is not a %s postcode", ap.PostCode, ap.State),
+			})
+		}
+	}
+
+	if ap.Suburb != "" {
+		suburbLocalities := gazetteer.LocalitiesBySuburb(ap.Suburb)
+		if len(suburbLocalities) == 0 {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "Suburb",
+				Message: fmt.Sprintf("%s is not a known suburb", ap.Suburb),
+			})
+			return validationErrors
+		}
+		if ap.PostCode != 0 && !localitiesHavePostCode(suburbLocalities, ap.PostCode) {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "Suburb",
+				Message: fmt.Sprintf("%s is not in %04d", ap.Suburb, ap.PostCode),
+			})
+		}
+		if ap.State != "" && !localitiesHaveState(suburbLocalities, ap.State) {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "Suburb",
+				Message: fmt.Sprintf("%s is not in %s", ap.Suburb, ap.State),
+			})
+		}
+	}
+
+	return validationErrors
+}
+
+func localitiesHaveState(localities []Locality, state string) bool {
+	for _, locality := range localities {
+		if locality.State == state {
+			return true
+		}
+	}
+	return false
+}
+
+func localitiesHavePostCode(localities []Locality, postCode int) bool {
+	for _, locality := range localities {
+		if locality.PostCode == postCode {
+			return true
+		}
+	}
+	return false
+}
+
+// get the state for a postcode from the australia post ranges
+func postCodeState(postCode int) string {
+	for _, postCodeRange := range postCodeRanges {
+		if postCode >= postCodeRange.Start && postCode <= postCodeRange.End {
+			return postCodeRange.State
+		}
+	}
+	return ""
+}
+
+// Enrich fill a missing state and postcode from the postcode and suburb,
+// inferred fields are recorded in InferredFields. gazetteer can be nil.
+func (ap *AddressParts) Enrich(gazetteer Gazetteer) {
+	if ap.State == "" && ap.PostCode != 0 {
+		if state := postCodeState(ap.PostCode); state != "" {
+			ap.State = state
+			ap.InferredFields = append(ap.InferredFields, "State")
+		}
+	}
+
+	if gazetteer == nil || ap.Suburb == "" || (ap.State != "" && ap.PostCode != 0) {
+		return
+	}
+
+	var states []string
+	var postCodes []int
+	for _, locality := range gazetteer.LocalitiesBySuburb(ap.Suburb) {
+		if ap.State != "" && locality.State != ap.State {
+			continue
+		}
+		if ap.PostCode != 0 && locality.PostCode != ap.PostCode {
+			continue
+		}
+		if !stringInSlice(locality.State, states) {
+			states = append(states, locality.State)
+		}
+		if !intInSlice(locality.PostCode, postCodes) {
+			postCodes = append(postCodes, locality.PostCode)
+		}
+	}
+
+	// only fill from an unambiguous suburb
+	if ap.State == "" && len(states) == 1 {
+		ap.State = states[0]
+		ap.InferredFields = append(ap.InferredFields, "State")
+	}
+	if ap.PostCode == 0 && len(postCodes) == 1 {
+		ap.PostCode = postCodes[0]
+		ap.InferredFields = append(ap.InferredFields, "PostCode")
+	}
+}
+
+func intInSlice(number int, list []int) bool {
+	for _, v := range list {
+		if v == number {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package addressparser
+
+import (
+	"strings"
+	"testing"
+)
+
+const testLocalityCSV = `suburb,state,postcode
+Melbourne,VIC,3000
+Melbourne,VIC,3004
+Sydney,NSW,2000
+Fitzroy,VIC,3065
+Alice Springs,NT,0870
+Hall's Creek,WA,6770
+`
+
+func TestLoadLocalityCSV(t *testing.T) {
+	gazetteer, err := LoadLocalityCSV(strings.NewReader(testLocalityCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	localities := gazetteer.LocalitiesBySuburb("melbourne")
+	if len(localities) != 2 {
+		t.Fatalf("expected 2 localities, actual %d", len(localities))
+	}
+	localities = gazetteer.LocalitiesByPostCode(870)
+	if len(localities) != 1 || localities[0].Suburb != "ALICE SPRINGS" {
+		t.Errorf("expected ALICE SPRINGS, actual %v", localities)
+	}
+	// suburbs are found by their name key
+	localities = gazetteer.LocalitiesBySuburb("Halls Creek")
+	if len(localities) != 1 || localities[0].Suburb != "HALL'S CREEK" {
+		t.Errorf("expected HALL'S CREEK, actual %v", localities)
+	}
+
+	_, err = LoadLocalityCSV(strings.NewReader("Sydney,NSW,2000\nBad,NSW,ABC\n"))
+	if err == nil {
+		t.Error("expected invalid postcode error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	gazetteer, err := LoadLocalityCSV(strings.NewReader(testLocalityCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validateTests := []struct {
+		address  string
+		expected []string
+	}{
+		{"1234 hello st Melbourne VIC 3000", nil},
+		{"1234 hello st Melbourne NSW 3000", []string{
+			"3000 is not a NSW postcode",
+			"MELBOURNE is not in NSW",
+		}},
+		{"1 George St Melbourne VIC 2000", []string{
+			"2000 is not a VIC postcode",
+			"MELBOURNE is not in 2000",
+		}},
+		{"1 George St Nowhere NSW 2999", []string{
+			"2999 is not a known postcode",
+			"NOWHERE is not a known suburb",
+		}},
+	}
+
+	for _, validateTest := range validateTests {
+		addressParts, err := NewAddress(validateTest.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		validationErrors := addressParts.Validate(gazetteer)
+		if len(validationErrors) != len(validateTest.expected) {
+			t.Errorf("%s: expected %v, actual %v", validateTest.address, validateTest.expected, validationErrors)
+			continue
+		}
+		for i, validationError := range validationErrors {
+			if validationError.Error() != validateTest.expected[i] {
+				errorExpectedString(t, validateTest.expected[i], validationError.Error())
+			}
+		}
+	}
+}
+
+func TestEnrich(t *testing.T) {
+	gazetteer, err := LoadLocalityCSV(strings.NewReader(testLocalityCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enrichTests := []struct {
+		address  string
+		postCode int
+		state    string
+		inferred []string
+	}{
+		{"Level 11 500 Collins St Melbourne 3000", 3000, "VIC", []string{"State"}},
+		{"12 Smith St Fitzroy", 3065, "VIC", []string{"State", "PostCode"}},
+		{"12 Smith St Melbourne", 0, "VIC", []string{"State"}},
+		{"12 Smith St Melbourne VIC 3000", 3000, "VIC", nil},
+		{"12 Smith St Nowhere", 0, "", nil},
+	}
+
+	for _, enrichTest := range enrichTests {
+		addressParts, err := NewAddress(enrichTest.address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		addressParts.Enrich(gazetteer)
+		if addressParts.PostCode != enrichTest.postCode {
+			errorExpectedInt(t, enrichTest.postCode, addressParts.PostCode)
+		}
+		if addressParts.State != enrichTest.state {
+			errorExpectedString(t, enrichTest.state, addressParts.State)
+		}
+		if strings.Join(addressParts.InferredFields, ",") != strings.Join(enrichTest.inferred, ",") {
+			errorExpectedString(t, strings.Join(enrichTest.inferred, ","), strings.Join(addressParts.InferredFields, ","))
+		}
+	}
+
+	// postcode ranges work without a gazetteer
+	addressParts, err := NewAddress("Level 11 500 Collins St Melbourne 3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addressParts.Enrich(nil)
+	if addressParts.State != "VIC" {
+		errorExpectedString(t, "VIC", addressParts.State)
+	}
+}
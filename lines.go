@@ -0,0 +1,220 @@
+package addressparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// AddressLines - an address as the separate fields of a form or database, eg.
+// address line 1 and 2, suburb, state and postcode. fields can be empty,
+// partly wrong or swapped, see ParseLines
+type AddressLines struct {
+	// address lines in order, eg. the flat, building and street
+	Lines    []string
+	Suburb   string
+	State    string
+	PostCode string
+}
+
+// locality found in the address lines, uppercased and cleaned
+type lineLocality struct {
+	suburb   string
+	state    string
+	postCode string
+}
+
+// ParseLines parse address lines and locality fields into one address
+func ParseLines(lines AddressLines) (*AddressParts, error) {
+	return defaultParser.ParseLines(lines)
+}
+
+// ParseLines parse address lines with the parser. the suburb, state and postcode
+// fields are used for what they have in them rather than their name, so swapped
+// fields are put right. a line that is a locality, eg. RICHMOND VIC 3121 or 3121,
+// fills the fields that are empty wherever it is, and the rest of the lines are
+// parsed joined by line breaks, which Spans are positions in. a locality that
+// disagrees with the fields is left over, see Check
+func (p *Parser) ParseLines(lines AddressLines) (*AddressParts, error) {
+	locality := p.resolveLocality(lines)
+	addressParts := &AddressParts{parser: p}
+
+	var streetLines []string
+	for _, line := range lines.Lines {
+		words := cleanWords(line)
+		if len(words) == 0 {
+			continue
+		}
+		if found, ok := p.localityLine(words, locality.suburb); ok {
+			addressParts.conflicts = append(addressParts.conflicts, p.mergeLocality(&locality, found)...)
+			continue
+		}
+		streetLines = append(streetLines, strings.TrimSpace(line))
+	}
+
+	if len(streetLines) > 0 {
+		err := addressParts.LoadAddressString(strings.Join(streetLines, "\n"))
+		// the locality fields make up a short street line, eg. SHOP 12
+		if err != nil && !(IsParseError(err, ErrAddressTooShort) && locality != lineLocality{}) {
+			return addressParts, err
+		}
+		// a line can clean to nothing, eg. &
+		if len(addressParts.AddressStringParts) > 0 {
+			addressParts.ProcessAddress()
+		}
+	}
+
+	// a locality parsed from a street line, eg. 12 SMITH ST RICHMOND VIC 3121
+	parsed := lineLocality{
+		suburb:   addressParts.Suburb,
+		state:    addressParts.State,
+		postCode: addressParts.formatPostCode(),
+	}
+	addressParts.conflicts = append(addressParts.conflicts, p.mergeLocality(&locality, parsed)...)
+	p.setLocality(addressParts, locality, parsed)
+
+	for _, step := range p.enrichSteps {
+		step(addressParts)
+	}
+	if p.strict {
+		return addressParts, addressParts.Check()
+	}
+	return addressParts, nil
+}
+
+// suburb, state and postcode from the locality fields. each field is used for
+// its own part first, then the other fields in order
+func (p *Parser) resolveLocality(lines AddressLines) lineLocality {
+	suburb := p.splitLocality(cleanWords(lines.Suburb), false)
+	state := p.splitLocality(cleanWords(lines.State), false)
+	postCode := p.splitLocality(cleanWords(lines.PostCode), false)
+
+	return lineLocality{
+		suburb:   firstNonEmpty(suburb.suburb, state.suburb, postCode.suburb),
+		state:    firstNonEmpty(state.state, suburb.state, postCode.state),
+		postCode: firstNonEmpty(postCode.postCode, suburb.postCode, state.postCode),
+	}
+}
+
+// the locality of an address line, if the line is a locality, eg. RICHMOND VIC 3121,
+// VIC 3121, 3121 or the suburb of the fields
+func (p *Parser) localityLine(words []string, suburb string) (lineLocality, bool) {
+	if suburb != "" && NameKey(strings.Join(words, " ")) == NameKey(suburb) {
+		return lineLocality{suburb: suburb}, true
+	}
+	found := p.splitLocality(words, true)
+	return found, found.state != "" || found.postCode != ""
+}
+
+// fill the empty parts of the locality from a found locality, returning the
+// found parts that disagree with it
+func (p *Parser) mergeLocality(locality *lineLocality, found lineLocality) []string {
+	var conflicts []string
+	if locality.suburb == "" {
+		locality.suburb = found.suburb
+	} else if found.suburb != "" && NameKey(found.suburb) != NameKey(locality.suburb) {
+		conflicts = append(conflicts, found.suburb)
+	}
+	if locality.state == "" {
+		locality.state = found.state
+	} else if found.state != "" && p.dictionaries.States.Key(found.state) != p.dictionaries.States.Key(locality.state) {
+		conflicts = append(conflicts, found.state)
+	}
+	if locality.postCode == "" {
+		locality.postCode = found.postCode
+	} else if found.postCode != "" && found.postCode != locality.postCode {
+		conflicts = append(conflicts, found.postCode)
+	}
+	return conflicts
+}
+
+// set the locality of an address, a parsed part that was replaced loses its span and match
+func (p *Parser) setLocality(ap *AddressParts, locality lineLocality, parsed lineLocality) {
+	ap.Suburb = locality.suburb
+	ap.State = p.dictionaries.States.Key(locality.state)
+	ap.PostCode, _ = strconv.Atoi(locality.postCode)
+
+	for field, replaced := range map[string]bool{
+		"Suburb":   NameKey(parsed.suburb) != NameKey(locality.suburb),
+		"State":    parsed.state != ap.State,
+		"PostCode": parsed.postCode != locality.postCode,
+	} {
+		if replaced {
+			delete(ap.Spans, field)
+			delete(ap.Matches, field)
+		}
+	}
+}
+
+// split a state and postcode from the end of words, the rest is the suburb.
+// on an address line the rest must be words that aren't a type the number
+// belongs to, so the number of SHOP 1234, PO BOX 1234 or a street is not the postcode
+func (p *Parser) splitLocality(words []string, line bool) lineLocality {
+	var locality lineLocality
+	rest := words
+
+	if len(rest) > 0 {
+		last := rest[len(rest)-1]
+		if isDigits(last) && (len(last) == 4 || !line && len(last) < 4) {
+			// a postcode field can be short, eg. 870 for 0870
+			locality.postCode = strings.Repeat("0", 4-len(last)) + last
+			rest = rest[:len(rest)-1]
+		}
+	}
+	for length := 3; length > 0; length-- {
+		if len(rest) < length {
+			continue
+		}
+		state := strings.Join(rest[len(rest)-length:], " ")
+		if p.dictionaries.States.Has(state) {
+			locality.state = state
+			rest = rest[:len(rest)-length]
+			break
+		}
+	}
+
+	if line && len(rest) > 0 && (!areWords(rest) || p.isTypedNumber(rest)) {
+		// not a locality, eg. PO BOX 1234
+		return lineLocality{suburb: strings.Join(words, " ")}
+	}
+	locality.suburb = strings.Join(rest, " ")
+	return locality
+}
+
+// check words are a type the number after them belongs to, eg. PO BOX, SHOP or LEVEL
+func (p *Parser) isTypedNumber(words []string) bool {
+	compact := strings.Join(words, "")
+	for _, dict := range []*Dictionary{
+		p.dictionaries.PostalDeliveryTypes, p.dictionaries.FlatTypes,
+		p.dictionaries.LevelTypes, p.dictionaries.LotTypes,
+	} {
+		if _, ok := dict.compactKeys[compact]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// the words of an address line, cleaned the same as an address
+func cleanWords(line string) []string {
+	cleaned, _ := cleanAddressString(line)
+	return strings.Fields(strings.Replace(cleaned, segmentMarker, " ", -1))
+}
+
+func areWords(words []string) bool {
+	for _, word := range words {
+		if !isWord(word) {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// first item that isn't empty
+func firstNonEmpty(items ...string) string {
+	for _, item := range items {
+		if item != "" {
+			return item
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package addressparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLines(t *testing.T) {
+	linesTests := []struct {
+		lines    AddressLines
+		expected string
+		leftover bool
+	}{
+		{
+			AddressLines{Lines: []string{"Unit 4", "12 Smith St"}, Suburb: "Richmond", State: "VIC", PostCode: "3121"},
+			"UNIT 4, 12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		// the locality in line 2
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Richmond VIC 3121"}},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Richmond 3121"}, State: "VIC"},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		// line 2 repeats the fields
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Richmond VIC 3121"}, Suburb: "richmond", State: "Victoria", PostCode: "3121"},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		// swapped fields
+		{
+			AddressLines{Lines: []string{"12 Smith St"}, Suburb: "3121", State: "Richmond", PostCode: "VIC"},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		{
+			AddressLines{Lines: []string{"Rialto Towers", "525 Collins St", ""}, Suburb: "Melbourne VIC 3000"},
+			"RIALTO TOWERS, 525 COLLINS ST, MELBOURNE VIC 3000", false,
+		},
+		// the box number is not the postcode
+		{
+			AddressLines{Lines: []string{"PO Box 1234"}, Suburb: "Melbourne", State: "VIC"},
+			"PO BOX 1234, MELBOURNE VIC", false,
+		},
+		// a line that is only the postcode, or the state and postcode
+		{
+			AddressLines{Lines: []string{"12 Smith St", "3121"}, Suburb: "Richmond", State: "VIC"},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Vic 3121"}, Suburb: "Richmond"},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		// the locality on any line
+		{
+			AddressLines{Lines: []string{"Richmond VIC 3121", "12 Smith St"}},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		{
+			AddressLines{Lines: []string{"12 Smith St Richmond VIC 3121"}},
+			"12 SMITH ST, RICHMOND VIC 3121", false,
+		},
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Richmond VIC 3121", "Attn Accounts"}},
+			"12 SMITH ST, RICHMOND VIC 3121", true,
+		},
+		// a short postcode field
+		{
+			AddressLines{Lines: []string{"PO Box 12"}, Suburb: "Darwin", State: "NT", PostCode: "870"},
+			"PO BOX 12, DARWIN NT 0870", false,
+		},
+		// the shop number is not the postcode, there is no street
+		{
+			AddressLines{Lines: []string{"Shop 1234"}, Suburb: "Richmond"},
+			"SHOP 1234, RICHMOND", true,
+		},
+		// the state field wins, the line state is left over
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Richmond NSW 3121"}, State: "VIC"},
+			"12 SMITH ST, RICHMOND VIC 3121", true,
+		},
+		// the postcode field wins, the line postcode is left over
+		{
+			AddressLines{Lines: []string{"12 Smith St", "Richmond VIC 3122"}, PostCode: "3121"},
+			"12 SMITH ST, RICHMOND VIC 3121", true,
+		},
+	}
+
+	for _, linesTest := range linesTests {
+		addressParts, err := ParseLines(linesTest.lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addressParts.String() != linesTest.expected {
+			errorExpectedString(t, linesTest.expected, addressParts.String())
+		}
+		if err := addressParts.Check(); (err != nil) != linesTest.leftover {
+			t.Errorf("%v: expected left over %t, actual %v", linesTest.lines, linesTest.leftover, err)
+		}
+	}
+
+	// the conflicting state is left over rather than parsed into the suburb
+	addressParts, err := ParseLines(AddressLines{
+		Lines: []string{"John Smith 12 Smith St", "Richmond NSW 3121"},
+		State: "VIC",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	parseError, ok := addressParts.Check().(*ParseError)
+	if !ok || parseError.Kind != ErrLeftoverParts || len(parseError.Conflicts) != 1 || parseError.Conflicts[0] != "NSW" {
+		t.Fatalf("expected NSW left over, actual %v", addressParts.Check())
+	}
+	if len(parseError.Parts) != 2 || len(parseError.Parts) != len(parseError.Positions) {
+		t.Errorf("expected a position for each part, actual %v and %v", parseError.Parts, parseError.Positions)
+	}
+}
+
+func TestParseLinesNoParts(t *testing.T) {
+	// street lines that clean to nothing leave only the locality
+	for _, lines := range []AddressLines{
+		{Lines: []string{"&"}, Suburb: "Richmond"},
+		{Lines: []string{"&", "A"}, Suburb: "A"},
+	} {
+		addressParts, err := ParseLines(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addressParts.Suburb != strings.ToUpper(lines.Suburb) {
+			errorExpectedString(t, strings.ToUpper(lines.Suburb), addressParts.Suburb)
+		}
+		if addressParts.StreetName != "" {
+			errorExpectedString(t, "", addressParts.StreetName)
+		}
+	}
+}
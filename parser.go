@@ -0,0 +1,227 @@
+package addressparser
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultLocale - the locale of the default dictionaries
+const DefaultLocale = "AU"
+
+// dictionaries of each supported locale
+var localeDictionaries = map[string]*Dictionaries{
+	DefaultLocale: defaultDictionaries,
+}
+
+// Parser - parses addresses with its own options. a parser is never changed
+// once created, so one parser can be used by many goroutines.
+type Parser struct {
+	dictionaries *Dictionaries
+	locale       string
+	similarity   Similarity
+	// fuzzy matches must be closer than the threshold
+	threshold float64
+	// threshold of each dictionary by name, then by dictionary
+	namedThresholds map[string]float64
+	thresholds      map[*Dictionary]float64
+	strict          bool
+	trace           bool
+	// known building names and the most words in one
+	buildingNames      map[string]bool
+	buildingNameLength int
+	enrichSteps        []EnrichStep
+}
+
+// ParserOption - option to configure a Parser, an invalid option fails NewParser
+type ParserOption func(*Parser) error
+
+// EnrichStep - a step run on each address after it is parsed
+type EnrichStep func(*AddressParts)
+
+// parser used by NewAddress and the other package functions
+var defaultParser = &Parser{
+	dictionaries: defaultDictionaries,
+	locale:       DefaultLocale,
+	similarity:   FuzzySearch{},
+	threshold:    FuzzySearchThreshold,
+}
+
+// WithDictionaries parse with dictionaries other than the defaults
+func WithDictionaries(dictionaries *Dictionaries) ParserOption {
+	return func(p *Parser) error {
+		if dictionaries == nil {
+			return errors.New("Missing dictionaries")
+		}
+		// copy so extending the dictionaries later doesn't change the parser
+		copied := *dictionaries
+		// dictionaries left out are the defaults
+		for _, name := range dictionaryNames {
+			if dict := copied.byName(name); *dict == nil {
+				*dict = *defaultDictionaries.byName(name)
+			}
+		}
+		p.dictionaries = &copied
+		return nil
+	}
+}
+
+// WithLocale parse with the dictionaries of a locale, only AU is supported
+func WithLocale(locale string) ParserOption {
+	return func(p *Parser) error {
+		locale = strings.ToUpper(strings.TrimSpace(locale))
+		dictionaries, ok := localeDictionaries[locale]
+		if !ok {
+			return errors.Errorf("Unknown locale %s", locale)
+		}
+		p.locale = locale
+		p.dictionaries = dictionaries
+		return nil
+	}
+}
+
+// WithFuzzyScore set the distance a fuzzy matched address type must be
+// closer than, lower is stricter
+func WithFuzzyScore(score int) ParserOption {
+	return func(p *Parser) error {
+		p.threshold = float64(score)
+		return nil
+	}
+}
+
+// WithSimilarity fuzzy match address types with a similarity, they must be
+// closer than the threshold, eg. WithSimilarity(Damerau{}, EditThreshold).
+// similarities other than FuzzySearch only match parts of 4 letters or fewer
+// exactly and scale the threshold by the part length up to 12 letters, so
+// EditThreshold allows one edit in STRET but not HEAD for LEAD
+func WithSimilarity(similarity Similarity, threshold float64) ParserOption {
+	return func(p *Parser) error {
+		p.similarity = similarity
+		p.threshold = threshold
+		return nil
+	}
+}
+
+// WithThreshold set the threshold of one dictionary by name, the names are
+// listed in LoadJSON
+func WithThreshold(dictionary string, threshold float64) ParserOption {
+	return func(p *Parser) error {
+		name := strings.ToLower(strings.TrimSpace(dictionary))
+		if new(Dictionaries).byName(name) == nil {
+			return errors.Errorf("Unknown dictionary %s", dictionary)
+		}
+		if p.namedThresholds == nil {
+			p.namedThresholds = make(map[string]float64)
+		}
+		p.namedThresholds[name] = threshold
+		return nil
+	}
+}
+
+// WithStrict fail addresses with unknown parts, see NewAddressStrict
+func WithStrict(strict bool) ParserOption {
+	return func(p *Parser) error {
+		p.strict = strict
+		return nil
+	}
+}
+
+// WithBuildingNames building names to look for before parsing the rest of
+// the address, eg. a building named like a street, ROSE COURT
+func WithBuildingNames(names ...string) ParserOption {
+	return func(p *Parser) error {
+		buildingNames := make(map[string]bool, len(p.buildingNames)+len(names))
+		for name := range p.buildingNames {
+			buildingNames[name] = true
+		}
+		for _, name := range names {
+			// clean the name the same as an address
+			cleaned, _ := cleanAddressString(name)
+			parts := strings.Fields(strings.Replace(cleaned, segmentMarker, " ", -1))
+			if len(parts) == 0 {
+				continue
+			}
+			buildingNames[NameKey(strings.Join(parts, " "))] = true
+			if len(parts) > p.buildingNameLength {
+				p.buildingNameLength = len(parts)
+			}
+		}
+		p.buildingNames = buildingNames
+		return nil
+	}
+}
+
+// WithTrace record the steps of each parse in the Trace of the address
+func WithTrace(trace bool) ParserOption {
+	return func(p *Parser) error {
+		p.trace = trace
+		return nil
+	}
+}
+
+// WithEnrichment run steps on each address after it is parsed, in order
+func WithEnrichment(steps ...EnrichStep) ParserOption {
+	return func(p *Parser) error {
+		p.enrichSteps = append(p.enrichSteps, steps...)
+		return nil
+	}
+}
+
+// EnrichFromGazetteer enrichment step to fill in the state and postcode, see Enrich
+func EnrichFromGazetteer(gazetteer Gazetteer) EnrichStep {
+	return func(ap *AddressParts) {
+		ap.Enrich(gazetteer)
+	}
+}
+
+// NewParser create a parser, with no options it parses the same as NewAddress
+func NewParser(options ...ParserOption) (*Parser, error) {
+	parser := *defaultParser
+	for _, option := range options {
+		if err := option(&parser); err != nil {
+			return nil, err
+		}
+	}
+
+	parser.thresholds = make(map[*Dictionary]float64, len(parser.namedThresholds))
+	for name, threshold := range parser.namedThresholds {
+		parser.thresholds[*parser.dictionaries.byName(name)] = threshold
+	}
+	return &parser, nil
+}
+
+// fuzzy match an address part to a dictionary with the parser similarity
+func (p *Parser) fuzzyMatch(str string, dict *Dictionary) ([]string, float64) {
+	threshold, ok := p.thresholds[dict]
+	if !ok {
+		threshold = p.threshold
+	}
+	if strings.ContainsAny(str, "'-") {
+		str = NameKey(str)
+	}
+	return fuzzyMatch(str, dict, p.similarity, threshold)
+}
+
+// Locale get the locale of the parser
+func (p *Parser) Locale() string {
+	return p.locale
+}
+
+// Parse parse an address string into address struct
+func (p *Parser) Parse(address string) (*AddressParts, error) {
+	addressParts := &AddressParts{parser: p}
+	err := addressParts.LoadAddressString(address)
+	if err != nil {
+		return addressParts, err
+	}
+	addressParts.ProcessAddress()
+
+	for _, step := range p.enrichSteps {
+		step(addressParts)
+	}
+	if p.strict {
+		return addressParts, addressParts.Check()
+	}
+
+	return addressParts, nil
+}
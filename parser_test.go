@@ -0,0 +1,170 @@
+package addressparser
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParserFuzzyScore(t *testing.T) {
+	address := "12 Smith Stret Richmond VIC 3121"
+
+	addressParts, err := newTestParser(t).Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.StreetType != "STREET" {
+		errorExpectedString(t, "STREET", addressParts.StreetType)
+	}
+
+	addressParts, err = newTestParser(t, WithFuzzyScore(1)).Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.StreetType != "" {
+		errorExpectedString(t, "", addressParts.StreetType)
+	}
+}
+
+func TestParserStrict(t *testing.T) {
+	parser := newTestParser(t, WithStrict(true))
+	_, err := parser.Parse("12 Smith Street Richmond VIC 3121")
+	if err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	_, err = parser.Parse("12 Smith Richmond VIC 3121")
+	if !IsParseError(err, ErrUnknownStreetType) {
+		t.Errorf("expected unknown street type error, actual %v", err)
+	}
+}
+
+func TestParserLocale(t *testing.T) {
+	parser := newTestParser(t, WithLocale("au"))
+	if parser.Locale() != DefaultLocale {
+		errorExpectedString(t, DefaultLocale, parser.Locale())
+	}
+
+	if _, err := NewParser(WithLocale("XX")); err == nil {
+		t.Error("expected unknown locale error")
+	}
+}
+
+func TestParserEnrichment(t *testing.T) {
+	gazetteer, err := LoadLocalityCSV(strings.NewReader(testLocalityCSV))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var steps []string
+	parser := newTestParser(t, WithEnrichment(
+		EnrichFromGazetteer(gazetteer),
+		func(ap *AddressParts) {
+			steps = append(steps, ap.State)
+		},
+	))
+
+	addressParts, err := parser.Parse("1 Smith St Fitzroy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.PostCode != 3065 {
+		errorExpectedInt(t, 3065, addressParts.PostCode)
+	}
+	if len(steps) != 1 || steps[0] != "VIC" {
+		t.Errorf("expected enrichment steps in order, actual %v", steps)
+	}
+}
+
+func TestParserConcurrent(t *testing.T) {
+	dictionaries := DefaultDictionaries()
+	if err := dictionaries.Extend("street_types", map[string]string{"RIDGEWAY": "RGWY"}); err != nil {
+		t.Fatal(err)
+	}
+	parsers := []*Parser{
+		newTestParser(t),
+		newTestParser(t, WithDictionaries(dictionaries), WithFuzzyScore(2)),
+		newTestParser(t, WithStrict(true)),
+	}
+	// changing the dictionaries after must not change the parser
+	if err := dictionaries.Extend("street_types", map[string]string{"RIDGEWAY": ""}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(parser *Parser, ridgeway bool) {
+			defer wg.Done()
+			addressParts, err := parser.Parse("12 Smith Ridgeway Richmond VIC 3121")
+			if err != nil && !IsParseError(err, ErrUnknownStreetType) {
+				t.Errorf("unexpected error %s", err)
+				return
+			}
+			if ridgeway && addressParts.StreetType != "RIDGEWAY" {
+				errorExpectedString(t, "RIDGEWAY", addressParts.StreetType)
+			}
+		}(parsers[i%len(parsers)], i%len(parsers) == 1)
+	}
+	wg.Wait()
+}
+
+func TestParserBuildingNames(t *testing.T) {
+	address := "525 Collins St Rialto Towers Melbourne VIC 3000"
+
+	addressParts, err := newTestParser(t).Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "" {
+		errorExpectedString(t, "", addressParts.BuildingName)
+	}
+
+	parser := newTestParser(t, WithBuildingNames("Rialto Towers", "The Glen"))
+	addressParts, err = parser.Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "RIALTO TOWERS" {
+		errorExpectedString(t, "RIALTO TOWERS", addressParts.BuildingName)
+	}
+	if addressParts.Suburb != "MELBOURNE" {
+		errorExpectedString(t, "MELBOURNE", addressParts.Suburb)
+	}
+	if addressParts.Original("BuildingName") != "Rialto Towers" {
+		errorExpectedString(t, "Rialto Towers", addressParts.Original("BuildingName"))
+	}
+	if err := addressParts.Check(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	// building names are matched without their apostrophes
+	parser = newTestParser(t, WithBuildingNames("St John's House"))
+	addressParts, err = parser.Parse("St Johns House 12 Smith St Fitzroy VIC 3065")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "ST JOHNS HOUSE" {
+		errorExpectedString(t, "ST JOHNS HOUSE", addressParts.BuildingName)
+	}
+
+	// a building name that is the whole address
+	parser = newTestParser(t, WithBuildingNames("Rialto Towers Melbourne"))
+	addressParts, err = parser.Parse("Rialto Towers Melbourne")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.BuildingName != "RIALTO TOWERS MELBOURNE" {
+		errorExpectedString(t, "RIALTO TOWERS MELBOURNE", addressParts.BuildingName)
+	}
+	if !IsParseError(addressParts.Check(), ErrNoStreetName) {
+		t.Errorf("expected no street name error, actual %v", addressParts.Check())
+	}
+}
+
+// create a parser, failing the test if an option is invalid
+func newTestParser(t *testing.T, options ...ParserOption) *Parser {
+	parser, err := NewParser(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parser
+}
@@ -0,0 +1,239 @@
+package addressparser
+
+import (
+	"strings"
+
+	"github.com/renstrom/fuzzysearch/fuzzy"
+)
+
+// Similarity - measures how far an address part is from a dictionary entry
+type Similarity interface {
+	// Distance get the distance from an address part to a dictionary entry,
+	// 0 is the same. ok is false if the entry can never match the part
+	Distance(part string, entry string) (distance float64, ok bool)
+}
+
+// default similarity thresholds, see WithSimilarity
+const (
+	FuzzySearchThreshold = 10
+	EditThreshold        = 3
+	JaroWinklerThreshold = 0.15
+)
+
+// similarity where the entry must have the letters of the part in order, see
+// FuzzySearch. other similarities only match short parts exactly and have the
+// threshold scaled by the part length, see WithSimilarity
+type orderedSimilarity interface {
+	// an entry ending with the part matches whatever the threshold
+	matchesSuffix(part string, entry string) bool
+}
+
+// parts this long or shorter only match exactly, longer parts have the threshold
+// scaled by their length up to fullThresholdLength
+const (
+	maxExactLength      = 4
+	fullThresholdLength = 12
+)
+
+// threshold of a part with a similarity that isn't ordered, eg. HEAD is too short
+// to be LEAD at EditThreshold
+func scaledThreshold(threshold float64, part string) float64 {
+	if len(part) >= fullThresholdLength {
+		return threshold
+	}
+	return threshold * float64(len(part)) / fullThresholdLength
+}
+
+// Match - the dictionary entry an address part matched and its distance
+type Match struct {
+	Part     string
+	Entry    string
+	Distance float64
+}
+
+// FuzzySearch - the default similarity, the entry must have the letters of the
+// part in order and the distance is the levenshtein distance. an entry ending
+// with the part matches whatever the threshold, eg. PDE matches PARADE
+type FuzzySearch struct{}
+
+// Distance levenshtein distance if the entry has the letters of the part
+func (FuzzySearch) Distance(part string, entry string) (float64, bool) {
+	if !fuzzy.Match(part, entry) {
+		return 0, false
+	}
+	return float64(fuzzy.LevenshteinDistance(part, entry)), true
+}
+
+// an entry ending with the part matches whatever the threshold
+func (FuzzySearch) matchesSuffix(part string, entry string) bool {
+	return strings.HasSuffix(entry, part)
+}
+
+// Levenshtein - the number of letters inserted, deleted or replaced
+type Levenshtein struct{}
+
+// Distance levenshtein distance from the part to the entry
+func (Levenshtein) Distance(part string, entry string) (float64, bool) {
+	return float64(fuzzy.LevenshteinDistance(part, entry)), true
+}
+
+// Damerau - levenshtein distance where swapping two letters is one edit, eg. STERET
+type Damerau struct{}
+
+// Distance damerau levenshtein distance from the part to the entry
+func (Damerau) Distance(part string, entry string) (float64, bool) {
+	return editDistance(part, entry, func(a, b byte) float64 {
+		return 1
+	}), true
+}
+
+// Keyboard - damerau distance where a letter replaced by one next to it on a
+// qwerty keyboard is half an edit, eg. STREWT
+type Keyboard struct{}
+
+// Distance keyboard weighted distance from the part to the entry
+func (Keyboard) Distance(part string, entry string) (float64, bool) {
+	return editDistance(part, entry, keyDistance), true
+}
+
+// JaroWinkler - 1 less the jaro winkler similarity, from 0 to 1. entries
+// starting with the same letters as the part are closer
+type JaroWinkler struct{}
+
+// Distance jaro winkler distance from the part to the entry
+func (JaroWinkler) Distance(part string, entry string) (float64, bool) {
+	return 1 - jaroWinkler(part, entry), true
+}
+
+// optimal string alignment distance with the cost of replacing a letter
+func editDistance(source string, target string, replaceCost func(a, b byte) float64) float64 {
+	rows := make([][]float64, len(source)+1)
+	for i := range rows {
+		rows[i] = make([]float64, len(target)+1)
+		rows[i][0] = float64(i)
+	}
+	for j := range rows[0] {
+		rows[0][j] = float64(j)
+	}
+
+	for i := 1; i <= len(source); i++ {
+		for j := 1; j <= len(target); j++ {
+			cost := 0.0
+			if source[i-1] != target[j-1] {
+				cost = replaceCost(source[i-1], target[j-1])
+			}
+			distance := rows[i-1][j] + 1
+			if rows[i][j-1]+1 < distance {
+				distance = rows[i][j-1] + 1
+			}
+			if rows[i-1][j-1]+cost < distance {
+				distance = rows[i-1][j-1] + cost
+			}
+			// swapped letters
+			if i > 1 && j > 1 && source[i-1] == target[j-2] && source[i-2] == target[j-1] &&
+				rows[i-2][j-2]+1 < distance {
+				distance = rows[i-2][j-2] + 1
+			}
+			rows[i][j] = distance
+		}
+	}
+	return rows[len(source)][len(target)]
+}
+
+// jaro winkler similarity, 1 is the same
+func jaroWinkler(source string, target string) float64 {
+	if source == target {
+		return 1
+	}
+	if source == "" || target == "" {
+		return 0
+	}
+
+	matchRange := len(source)
+	if len(target) > matchRange {
+		matchRange = len(target)
+	}
+	matchRange = matchRange/2 - 1
+	if matchRange < 0 {
+		matchRange = 0
+	}
+
+	sourceMatched := make([]bool, len(source))
+	targetMatched := make([]bool, len(target))
+	var matches float64
+	for i := range source {
+		start, end := i-matchRange, i+matchRange+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(target) {
+			end = len(target)
+		}
+		for j := start; j < end; j++ {
+			if !targetMatched[j] && source[i] == target[j] {
+				sourceMatched[i], targetMatched[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	var transpositions float64
+	j := 0
+	for i := range source {
+		if !sourceMatched[i] {
+			continue
+		}
+		for !targetMatched[j] {
+			j++
+		}
+		if source[i] != target[j] {
+			transpositions++
+		}
+		j++
+	}
+	jaro := (matches/float64(len(source)) + matches/float64(len(target)) +
+		(matches-transpositions/2)/matches) / 3
+
+	// bonus for a common prefix of up to 4 letters
+	var prefix float64
+	for i := 0; i < len(source) && i < len(target) && i < 4 && source[i] == target[i]; i++ {
+		prefix++
+	}
+	return jaro + prefix*0.1*(1-jaro)
+}
+
+// qwerty keyboard rows
+var keyboardRows = []string{"1234567890", "QWERTYUIOP", "ASDFGHJKL", "ZXCVBNM"}
+
+// cost of replacing a key, half for keys next to each other
+func keyDistance(a byte, b byte) float64 {
+	aRow, aColumn := keyPosition(a)
+	bRow, bColumn := keyPosition(b)
+	if aRow < 0 || bRow < 0 {
+		return 1
+	}
+	rowDistance, columnDistance := aRow-bRow, aColumn-bColumn
+	if rowDistance < 0 {
+		rowDistance = -rowDistance
+	}
+	if columnDistance < 0 {
+		columnDistance = -columnDistance
+	}
+	if rowDistance <= 1 && columnDistance <= 1 {
+		return 0.5
+	}
+	return 1
+}
+
+func keyPosition(key byte) (int, int) {
+	for row, keys := range keyboardRows {
+		if column := strings.IndexByte(keys, key); column >= 0 {
+			return row, column
+		}
+	}
+	return -1, -1
+}
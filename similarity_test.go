@@ -0,0 +1,117 @@
+package addressparser
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestSimilarityDistance(t *testing.T) {
+	tests := []struct {
+		similarity Similarity
+		part       string
+		entry      string
+		distance   float64
+		ok         bool
+	}{
+		{FuzzySearch{}, "STRET", "STREET", 1, true},
+		{FuzzySearch{}, "STERET", "STREET", 0, false},
+		{Levenshtein{}, "STERET", "STREET", 2, true},
+		{Damerau{}, "STERET", "STREET", 1, true},
+		{Damerau{}, "AVNUE", "AVENUE", 1, true},
+		{Keyboard{}, "STREWT", "STREET", 0.5, true},
+		{Keyboard{}, "STREPT", "STREET", 1, true},
+		{JaroWinkler{}, "STREET", "STREET", 0, true},
+		{JaroWinkler{}, "MARTHA", "MARHTA", 0.0389, true},
+		{JaroWinkler{}, "AB", "XY", 1, true},
+	}
+
+	for _, test := range tests {
+		distance, ok := test.similarity.Distance(test.part, test.entry)
+		if ok != test.ok || math.Abs(distance-test.distance) > 0.0001 {
+			t.Errorf("%T %s %s: expected %.4f %t, actual %.4f %t",
+				test.similarity, test.part, test.entry, test.distance, test.ok, distance, ok)
+		}
+	}
+}
+
+func TestParserSimilarity(t *testing.T) {
+	address := "12 Smith Steret Richmond VIC 3121"
+
+	addressParts, err := newTestParser(t).Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.StreetType != "" {
+		errorExpectedString(t, "", addressParts.StreetType)
+	}
+
+	parser := newTestParser(
+		t,
+		WithSimilarity(Damerau{}, 1),
+		WithThreshold("street_types", EditThreshold),
+	)
+	addressParts, err = parser.Parse(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.StreetType != "STREET" {
+		errorExpectedString(t, "STREET", addressParts.StreetType)
+	}
+	match := addressParts.Matches["StreetType"]
+	if match.Part != "STERET" || match.Entry != "STREET" || match.Distance != 1 {
+		t.Errorf("expected STERET matched STREET at 1, actual %v", match)
+	}
+	if match := addressParts.Matches["State"]; match.Entry != "VIC" || match.Distance != 0 {
+		t.Errorf("expected exact VIC match, actual %v", match)
+	}
+
+	_, err = NewParser(WithThreshold("streets", 1))
+	if err == nil {
+		t.Error("expected unknown dictionary error")
+	}
+}
+
+func TestFuzzySearchSuffix(t *testing.T) {
+	dict := NewDictionary(map[string]string{"PARADE": "PDE", "PARK": "PARK"})
+	for _, similarity := range []Similarity{FuzzySearch{}, &FuzzySearch{}} {
+		// an entry ending with the part matches beyond the threshold
+		result, _ := fuzzyMatch("RADE", dict, similarity, 1)
+		if len(result) != 1 || result[0] != "PARADE" {
+			t.Errorf("%T: expected PARADE, actual %v", similarity, result)
+		}
+	}
+	if result, _ := fuzzyMatch("RADE", dict, Levenshtein{}, 1); len(result) != 0 {
+		t.Errorf("expected no match, actual %v", result)
+	}
+}
+
+func TestAddressesSimilarity(t *testing.T) {
+	// the built in similarities with their documented thresholds parse the test addresses
+	similarities := []struct {
+		similarity Similarity
+		threshold  float64
+	}{
+		{FuzzySearch{}, FuzzySearchThreshold},
+		{Levenshtein{}, EditThreshold},
+		{Damerau{}, EditThreshold},
+		{Keyboard{}, EditThreshold},
+		{JaroWinkler{}, JaroWinklerThreshold},
+	}
+
+	for _, similarity := range similarities {
+		parser := newTestParser(t, WithSimilarity(similarity.similarity, similarity.threshold))
+		t.Run(fmt.Sprintf("%T", similarity.similarity), func(t *testing.T) {
+			for _, testAddress := range testAddresses {
+				addressParts, err := parser.Parse(testAddress.AddressString)
+				if err != nil {
+					t.Errorf("%s: %s", testAddress.AddressString, err)
+					continue
+				}
+				if !checkAddress(t, testAddress, addressParts) {
+					t.Errorf("%s: unexpected parse %s", testAddress.AddressString, addressParts)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package addressparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TraceStep - what a step of ProcessAddress looked at and what it took
+type TraceStep struct {
+	Step string
+	// address parts left when the step started, removed parts are empty
+	Parts []string
+	// indexes examined by findIndex and findIndexReverse
+	Examined []int
+	// dictionary matches attempted, an empty Entry did not match
+	Attempts []Match
+	// fields set by the step
+	Fields []string
+	// address parts removed by the step and their indexes
+	Removed        []string
+	RemovedIndexes []int
+}
+
+// Trace - the steps of a parse in order, see WithTrace
+type Trace []*TraceStep
+
+// String print the trace a step a line, with what each step found indented
+func (t Trace) String() string {
+	var lines []string
+	for _, step := range t {
+		lines = append(lines, fmt.Sprintf("%s: %s", step.Step, formatTraceParts(step.Parts)))
+		if len(step.Examined) > 0 {
+			lines = append(lines, fmt.Sprintf("  examined %s", joinInts(step.Examined)))
+		}
+		for _, attempt := range step.Attempts {
+			if attempt.Entry == "" {
+				lines = append(lines, fmt.Sprintf("  no match %s", attempt.Part))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  match %s = %s (%g)", attempt.Part, attempt.Entry, attempt.Distance))
+		}
+		if len(step.Fields) > 0 {
+			lines = append(lines, fmt.Sprintf("  set %s", strings.Join(step.Fields, " ")))
+		}
+		for i, part := range step.Removed {
+			lines = append(lines, fmt.Sprintf("  removed %d %s", step.RemovedIndexes[i], part))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parts with their indexes, eg. 0:UNIT 1:3, removed parts are skipped
+func formatTraceParts(parts []string) string {
+	var formatted []string
+	for index, part := range parts {
+		if part != "" {
+			formatted = append(formatted, fmt.Sprintf("%d:%s", index, part))
+		}
+	}
+	return strings.Join(formatted, " ")
+}
+
+func joinInts(values []int) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		formatted[i] = fmt.Sprint(value)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// start a step of the trace, if tracing
+func (ap *AddressParts) traceStep(step string) {
+	if ap.Trace == nil {
+		return
+	}
+	ap.Trace = append(ap.Trace, &TraceStep{
+		Step:  step,
+		Parts: append([]string{}, ap.AddressStringParts...),
+	})
+}
+
+// current step of the trace, nil if not tracing
+func (ap *AddressParts) currentStep() *TraceStep {
+	if len(ap.Trace) == 0 {
+		return nil
+	}
+	return ap.Trace[len(ap.Trace)-1]
+}
+
+func (ap *AddressParts) traceExamined(index int) {
+	if step := ap.currentStep(); step != nil {
+		step.Examined = append(step.Examined, index)
+	}
+}
+
+func (ap *AddressParts) traceAttempt(match Match) {
+	if step := ap.currentStep(); step != nil {
+		step.Attempts = append(step.Attempts, match)
+	}
+}
+
+// record a fuzzy match result as an attempt
+func (ap *AddressParts) traceMatch(part string, result []string, distance float64) {
+	if ap.currentStep() == nil || part == "" {
+		return
+	}
+	match := Match{Part: part}
+	if len(result) > 0 {
+		match.Entry = result[0]
+		match.Distance = distance
+	}
+	ap.traceAttempt(match)
+}
+
+func (ap *AddressParts) traceField(field string) {
+	if step := ap.currentStep(); step != nil && !stringInSlice(field, step.Fields) {
+		step.Fields = append(step.Fields, field)
+	}
+}
+
+func (ap *AddressParts) traceRemoved(index int) {
+	if step := ap.currentStep(); step != nil && ap.AddressStringParts[index] != "" {
+		step.Removed = append(step.Removed, ap.AddressStringParts[index])
+		step.RemovedIndexes = append(step.RemovedIndexes, index)
+	}
+}
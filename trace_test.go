@@ -0,0 +1,63 @@
+package addressparser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrace(t *testing.T) {
+	addressParts, err := NewAddress("Unit 3 12 Smith Stret Richmond VIC 3121")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addressParts.Trace != nil {
+		t.Error("expected no trace without WithTrace")
+	}
+
+	addressParts, err = newTestParser(t, WithTrace(true)).Parse("Unit 3 12 Smith Stret Richmond VIC 3121")
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := make(map[string]*TraceStep)
+	for _, step := range addressParts.Trace {
+		steps[step.Step] = step
+	}
+
+	flatType := steps["flat type"]
+	if flatType == nil {
+		t.Fatal("expected a flat type step")
+	}
+	if len(flatType.Examined) == 0 || flatType.Examined[0] != 1 {
+		t.Errorf("expected flat type to examine from index 1, actual %v", flatType.Examined)
+	}
+	if strings.Join(flatType.Removed, " ") != "3 UNIT" {
+		errorExpectedString(t, "3 UNIT", strings.Join(flatType.Removed, " "))
+	}
+	if strings.Join(flatType.Fields, " ") != "FlatType FlatNumber" {
+		errorExpectedString(t, "FlatType FlatNumber", strings.Join(flatType.Fields, " "))
+	}
+
+	streetType := steps["street type"]
+	if streetType == nil {
+		t.Fatal("expected a street type step")
+	}
+	var matched bool
+	for _, attempt := range streetType.Attempts {
+		if attempt.Part == "STRET" && attempt.Entry == "STREET" && attempt.Distance == 1 {
+			matched = true
+		}
+	}
+	if !matched {
+		t.Errorf("expected STRET matched to STREET, actual %v", streetType.Attempts)
+	}
+	if streetType.Parts[0] != "" || streetType.Parts[3] != "SMITH" {
+		t.Errorf("expected parts left before the step, actual %v", streetType.Parts)
+	}
+
+	trace := addressParts.Trace.String()
+	for _, line := range []string{"state: 2:12 3:SMITH 4:STRET 5:RICHMOND 6:VIC", "  match STRET = STREET (1)", "  removed 6 VIC"} {
+		if !strings.Contains(trace, line+"\n") {
+			t.Errorf("expected trace line %q in\n%s", line, trace)
+		}
+	}
+}